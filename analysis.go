@@ -0,0 +1,389 @@
+package passgen
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// WordPair is a pair of words from a word list.
+type WordPair struct {
+	First  string // First word of the pair.
+	Second string // Second word of the pair.
+}
+
+// AmbiguousConcatenation is two different pairs of words which join to the same string when no
+// separator is placed between them, such as "sun" "dial" and "sund" "ial".
+type AmbiguousConcatenation struct {
+	Joined string   // String both pairs join to.
+	First  WordPair // Pair whose first word is the shorter.
+	Second WordPair // Pair whose first word is the longer.
+}
+
+// WordListAnalysis describes how suitable a word list is for passphrases, particularly those whose
+// words are joined without a separator.
+type WordListAnalysis struct {
+	WordCount   uint    // Number of words in the list, including duplicates.
+	UniqueWords uint    // Number of unique words passphrases are drawn from.
+	BitsPerWord float64 // Bits of entropy contributed by each passphrase word.
+
+	PrefixWords             []WordPair               // Pairs where the first word is a prefix of the second.
+	AmbiguousConcatenations []AmbiguousConcatenation // Pairs of words which join to the same string.
+	SimilarWords            []WordPair               // Pairs of words one edit apart, which may be confused.
+	SoundAlikeWords         []WordPair               // Pairs of words sharing a phonetic key, which may sound alike.
+}
+
+// PrefixFree reports whether no word of the list is a prefix of another. The words of passphrases
+// drawn from a prefix-free list can always be told apart when joined without a separator.
+func (a WordListAnalysis) PrefixFree() bool {
+	return len(a.PrefixWords) == 0
+}
+
+// AnalyzeWordList analyzes the word list after removing duplicate words, as GeneratePassphrases
+// does with the default casing. Words are similar when one can be turned into the other by a single
+// insertion, deletion, or substitution of a character, or by swapping two adjacent characters,
+// which catches most near-duplicates. Words sound alike when they share a phonetic key, which catches
+// many English homophones such as "there" and "their" at the cost of some words which merely share
+// their consonants. Each list of pairs is sorted.
+func AnalyzeWordList(wordList []string) WordListAnalysis {
+	words := PassphraseOptions{Casing: PassphraseCasingDefault, WordList: wordList}.uniqueWords()
+
+	analysis := WordListAnalysis{
+		WordCount:   uint(len(wordList)),
+		UniqueWords: uint(len(words)),
+	}
+	if len(words) > 0 {
+		analysis.BitsPerWord = math.Log2(float64(len(words)))
+	}
+
+	// Sort the words, which places every word directly before the words it is a prefix of.
+	sorted := append([]string{}, words...)
+	sort.Strings(sorted)
+
+	analysis.PrefixWords = prefixWords(sorted)
+	analysis.AmbiguousConcatenations = ambiguousConcatenations(sorted, analysis.PrefixWords)
+	analysis.SimilarWords = similarWords(sorted)
+	analysis.SoundAlikeWords = soundAlikeWords(sorted)
+
+	return analysis
+}
+
+// prefixWords returns every pair of words in the sorted list where the first is a prefix of the
+// second.
+func prefixWords(sorted []string) (pairs []WordPair) {
+	for i, prefix := range sorted {
+		for _, word := range sorted[i+1:] {
+			if !strings.HasPrefix(word, prefix) {
+				break
+			}
+			pairs = append(pairs, WordPair{prefix, word})
+		}
+	}
+
+	return
+}
+
+// ambiguousConcatenations returns every pair of word pairs from the sorted list which join to the
+// same string. Such pairs can only arise from a word which is a prefix of another: if prefix+a and
+// word+b are equal, a must begin with the remainder of word after prefix, followed by b.
+func ambiguousConcatenations(sorted []string, prefixPairs []WordPair) (ambiguous []AmbiguousConcatenation) {
+	words := make(map[string]struct{}, len(sorted))
+	for _, word := range sorted {
+		words[word] = struct{}{}
+	}
+
+	for _, pair := range prefixPairs {
+		remainder := pair.Second[len(pair.First):]
+
+		// Step through the words beginning with the remainder, checking whether the rest of each is
+		// itself a word.
+		for _, word := range sorted[sort.SearchStrings(sorted, remainder):] {
+			if !strings.HasPrefix(word, remainder) {
+				break
+			}
+
+			rest := word[len(remainder):]
+			if _, ok := words[rest]; !ok || rest == "" {
+				continue
+			}
+
+			ambiguous = append(ambiguous, AmbiguousConcatenation{
+				Joined: pair.First + word,
+				First:  WordPair{pair.First, word},
+				Second: WordPair{pair.Second, rest},
+			})
+		}
+	}
+
+	return
+}
+
+// similarWords returns every pair of words in the sorted list which are one edit apart.
+//
+// Rather than comparing every pair of words, each word is indexed under itself and each string
+// formed by deleting one of its characters. Words one edit apart always share at least one such key,
+// so only words sharing a key need to be compared.
+func similarWords(sorted []string) (pairs []WordPair) {
+	// Index each word under its keys.
+	index := map[string][]int{}
+	for i, word := range sorted {
+		runes := []rune(word)
+
+		keys := map[string]struct{}{word: {}}
+		for j := range runes {
+			keys[string(runes[:j])+string(runes[j+1:])] = struct{}{}
+		}
+
+		for key := range keys {
+			index[key] = append(index[key], i)
+		}
+	}
+
+	// Compare the words sharing each key, recording each similar pair once.
+	seen := map[[2]int]struct{}{}
+	for _, indices := range index {
+		for j, first := range indices {
+			for _, second := range indices[j+1:] {
+				if _, ok := seen[[2]int{first, second}]; ok {
+					continue
+				}
+				seen[[2]int{first, second}] = struct{}{}
+
+				if oneEditApart([]rune(sorted[first]), []rune(sorted[second])) {
+					pairs = append(pairs, WordPair{sorted[first], sorted[second]})
+				}
+			}
+		}
+	}
+
+	// Sort the pairs, as the index is visited in no particular order.
+	sortWordPairs(pairs)
+
+	return
+}
+
+// soundAlikeWords returns every pair of words in the sorted list which share a phonetic key. Words
+// without a key, such as those written in a script other than Latin, are never paired.
+func soundAlikeWords(sorted []string) (pairs []WordPair) {
+	// Index each word under its key. The words under each key remain sorted.
+	index := map[string][]string{}
+	for _, word := range sorted {
+		if key := phoneticKey(word); key != "" {
+			index[key] = append(index[key], word)
+		}
+	}
+
+	// Pair every word with each later word sharing its key.
+	for _, words := range index {
+		for i, first := range words {
+			for _, second := range words[i+1:] {
+				pairs = append(pairs, WordPair{first, second})
+			}
+		}
+	}
+
+	// Sort the pairs, as the index is visited in no particular order.
+	sortWordPairs(pairs)
+
+	return
+}
+
+// sortWordPairs sorts the pairs by their first word, then by their second.
+func sortWordPairs(pairs []WordPair) {
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].First != pairs[j].First {
+			return pairs[i].First < pairs[j].First
+		}
+		return pairs[i].Second < pairs[j].Second
+	})
+}
+
+// oneEditApart reports whether the distinct words differ by a single insertion, deletion, or
+// substitution of a character, or by swapping two adjacent characters.
+func oneEditApart(a []rune, b []rune) bool {
+	// Ensure a is the shorter word.
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+
+	// Skip the characters the words begin with in common.
+	i := 0
+	for i < len(a) && a[i] == b[i] {
+		i++
+	}
+
+	switch len(b) - len(a) {
+	case 0:
+		if i == len(a) {
+			return false
+		}
+
+		// The rest must match after substituting the character, or after swapping it with the next.
+		if string(a[i+1:]) == string(b[i+1:]) {
+			return true
+		}
+		return i+1 < len(a) && a[i] == b[i+1] && a[i+1] == b[i] && string(a[i+2:]) == string(b[i+2:])
+
+	case 1:
+		// The rest must match after deleting the character from the longer word.
+		return string(a[i:]) == string(b[i+1:])
+
+	default:
+		return false
+	}
+}
+
+// phoneticKey returns the key of the word under the original Metaphone algorithm, which encodes how
+// an English word sounds by its consonants. Accents are removed and any character other than a Latin
+// letter is ignored, so words with no Latin letters have an empty key.
+//
+// Vowels are only encoded at the beginning of the word, so words such as "bat" and "bit" share a
+// key. Silent letters are dropped, as in "knight" and "night", and letters sounding alike are
+// encoded the same, as in "cite" and "site". Theta ('0') encodes "th", and 'X' encodes "sh".
+func phoneticKey(word string) string {
+	// Reduce the word to its uppercase Latin letters.
+	var letters []byte
+	for _, char := range norm.NFD.String(word) {
+		switch {
+		case char >= 'a' && char <= 'z':
+			letters = append(letters, byte(char-'a'+'A'))
+		case char >= 'A' && char <= 'Z':
+			letters = append(letters, byte(char))
+		}
+	}
+	if len(letters) == 0 {
+		return ""
+	}
+
+	// Drop the silent first letter of words such as "knight" and "write", and encode the beginning of
+	// words such as "xylophone" and "whole" as they sound.
+	if len(letters) > 1 {
+		switch string(letters[:2]) {
+		case "AE", "GN", "KN", "PN", "WR":
+			letters = letters[1:]
+		case "WH":
+			letters = append([]byte{'W'}, letters[2:]...)
+		}
+	}
+	if letters[0] == 'X' {
+		letters[0] = 'S'
+	}
+
+	// at returns the letter at the index, or zero beyond either end of the word.
+	at := func(i int) byte {
+		if i < 0 || i >= len(letters) {
+			return 0
+		}
+		return letters[i]
+	}
+
+	// Encode each letter by the letters around it.
+	var key []byte
+	for i, letter := range letters {
+		prev, next := at(i-1), at(i+1)
+
+		// Doubled letters sound as one, except for the "cc" of words such as "accept".
+		if letter == prev && letter != 'C' {
+			continue
+		}
+
+		switch letter {
+		case 'A', 'E', 'I', 'O', 'U':
+			if i == 0 {
+				key = append(key, letter)
+			}
+		case 'B':
+			// The "b" of a word ending in "mb", such as "thumb", is silent.
+			if !(prev == 'M' && i == len(letters)-1) {
+				key = append(key, 'B')
+			}
+		case 'C':
+			switch {
+			case next == 'I' && at(i+2) == 'A', next == 'H' && prev != 'S':
+				key = append(key, 'X')
+			case strings.IndexByte("EIY", next) >= 0:
+				// The "c" of "sce", "sci", and "scy" is silent.
+				if prev != 'S' {
+					key = append(key, 'S')
+				}
+			default:
+				key = append(key, 'K')
+			}
+		case 'D':
+			if next == 'G' && strings.IndexByte("EIY", at(i+2)) >= 0 {
+				key = append(key, 'J')
+			} else {
+				key = append(key, 'T')
+			}
+		case 'G':
+			switch {
+			case next == 'H' && !isVowel(at(i+2)):
+				// The "g" of "gh" is silent unless a vowel follows, as in "night".
+			case next == 'N' && (i+2 == len(letters) || string(letters[i+2:]) == "ED"):
+				// The "g" of a word ending in "gn" or "gned", such as "sign", is silent.
+			case prev == 'D' && strings.IndexByte("EIY", next) >= 0:
+				// The "dg" of "edge" was encoded with the "d".
+			case strings.IndexByte("EIY", next) >= 0:
+				key = append(key, 'J')
+			default:
+				key = append(key, 'K')
+			}
+		case 'H':
+			// The "h" is only sounded before a vowel, and not as part of "ch", "gh", "ph", "sh", or "th".
+			if isVowel(next) && strings.IndexByte("CGPST", prev) < 0 {
+				key = append(key, 'H')
+			}
+		case 'K':
+			if prev != 'C' {
+				key = append(key, 'K')
+			}
+		case 'P':
+			if next == 'H' {
+				key = append(key, 'F')
+			} else {
+				key = append(key, 'P')
+			}
+		case 'Q':
+			key = append(key, 'K')
+		case 'S':
+			if next == 'H' || next == 'I' && strings.IndexByte("AO", at(i+2)) >= 0 {
+				key = append(key, 'X')
+			} else {
+				key = append(key, 'S')
+			}
+		case 'T':
+			switch {
+			case next == 'I' && strings.IndexByte("AO", at(i+2)) >= 0:
+				key = append(key, 'X')
+			case next == 'H':
+				key = append(key, '0')
+			case next == 'C' && at(i+2) == 'H':
+				// The "t" of "tch" is silent.
+			default:
+				key = append(key, 'T')
+			}
+		case 'V':
+			key = append(key, 'F')
+		case 'W', 'Y':
+			// The "w" and "y" are only sounded before a vowel.
+			if isVowel(next) {
+				key = append(key, letter)
+			}
+		case 'X':
+			key = append(key, 'K', 'S')
+		case 'Z':
+			key = append(key, 'S')
+		default:
+			key = append(key, letter)
+		}
+	}
+
+	return string(key)
+}
+
+// isVowel reports whether the uppercase Latin letter is a vowel.
+func isVowel(letter byte) bool {
+	return strings.IndexByte("AEIOU", letter) >= 0
+}
@@ -0,0 +1,222 @@
+package passgen
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeWordList(t *testing.T) {
+	type testDef struct {
+		name     string
+		wordList []string
+
+		uniqueWords uint
+		prefixes    []WordPair
+		ambiguous   []AmbiguousConcatenation
+		similar     []WordPair
+		soundAlike  []WordPair
+	}
+
+	var tests = []testDef{
+		{
+			"prefix-free",
+			[]string{"alfa", "bravo", "charlie", "delta"},
+			4,
+			nil,
+			nil,
+			nil,
+			nil,
+		},
+		{
+			"duplicates removed",
+			[]string{"alfa", "bravo", "alfa", "charlie", "bravo"},
+			3,
+			nil,
+			nil,
+			nil,
+			nil,
+		},
+		{
+			"prefix words",
+			[]string{"sun", "sunny", "sundial", "moon"},
+			4,
+			[]WordPair{{"sun", "sundial"}, {"sun", "sunny"}},
+			nil,
+			nil,
+			[]WordPair{{"sun", "sunny"}},
+		},
+		{
+			"ambiguous concatenations",
+			[]string{"can", "canal", "alarm", "arm", "pit"},
+			5,
+			[]WordPair{{"can", "canal"}},
+			[]AmbiguousConcatenation{
+				{"canalarm", WordPair{"can", "alarm"}, WordPair{"canal", "arm"}},
+			},
+			nil,
+			nil,
+		},
+		{
+			"similar words",
+			[]string{"form", "from", "fort", "forte", "cat", "act", "dog"},
+			7,
+			[]WordPair{{"fort", "forte"}},
+			nil,
+			[]WordPair{{"act", "cat"}, {"form", "fort"}, {"form", "from"}, {"fort", "forte"}},
+			[]WordPair{{"form", "from"}, {"fort", "forte"}},
+		},
+		{
+			"unicode similar words",
+			[]string{"café", "cafe", "naïve", "navy"},
+			4,
+			nil,
+			nil,
+			[]WordPair{{"cafe", "café"}},
+			[]WordPair{{"cafe", "café"}, {"navy", "naïve"}},
+		},
+		{
+			"homophones",
+			[]string{"there", "their", "knight", "night", "write", "rite", "sight", "cite", "ночь"},
+			9,
+			nil,
+			nil,
+			[]WordPair{{"cite", "rite"}, {"knight", "night"}, {"night", "sight"}, {"rite", "write"}},
+			[]WordPair{
+				{"cite", "sight"},
+				{"knight", "night"},
+				{"rite", "write"},
+				{"their", "there"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name,
+			func(t *testing.T) {
+				analysis := AnalyzeWordList(test.wordList)
+				require.EqualValues(t, len(test.wordList), analysis.WordCount)
+				require.Equal(t, test.uniqueWords, analysis.UniqueWords)
+				require.InDelta(t, math.Log2(float64(test.uniqueWords)), analysis.BitsPerWord, 1e-9)
+				require.Equal(t, test.prefixes, analysis.PrefixWords)
+				require.Equal(t, len(test.prefixes) == 0, analysis.PrefixFree())
+				require.Equal(t, test.ambiguous, analysis.AmbiguousConcatenations)
+				require.Equal(t, test.similar, analysis.SimilarWords)
+				require.Equal(t, test.soundAlike, analysis.SoundAlikeWords)
+			},
+		)
+	}
+
+	t.Run(
+		"built-in lists",
+		func(t *testing.T) {
+			// The EFF short list is designed to be prefix-free with no words one edit apart.
+			analysis := AnalyzeWordList(WordListEFFShort2)
+			require.True(t, analysis.PrefixFree())
+			require.Empty(t, analysis.AmbiguousConcatenations)
+			require.Empty(t, analysis.SimilarWords)
+
+			// The EFF large list is prefix-free, though its longer words may be similar.
+			analysis = AnalyzeWordList(WordListEFFLarge)
+			require.True(t, analysis.PrefixFree())
+			require.EqualValues(t, DiceWordListLength, analysis.UniqueWords)
+
+			// The BIP39 list contains words such as "can" and "canal".
+			analysis = AnalyzeWordList(WordListBIP39English)
+			require.False(t, analysis.PrefixFree())
+			require.Contains(t, analysis.PrefixWords, WordPair{"can", "canal"})
+			require.Contains(
+				t,
+				analysis.AmbiguousConcatenations,
+				AmbiguousConcatenation{"canalarm", WordPair{"can", "alarm"}, WordPair{"canal", "arm"}},
+			)
+		},
+	)
+
+	t.Run(
+		"empty list",
+		func(t *testing.T) {
+			analysis := AnalyzeWordList(nil)
+			require.Zero(t, analysis.UniqueWords)
+			require.Zero(t, analysis.BitsPerWord)
+			require.True(t, analysis.PrefixFree())
+		},
+	)
+}
+
+func TestOneEditApart(t *testing.T) {
+	type testDef struct {
+		a, b    string
+		similar bool
+	}
+
+	var tests = []testDef{
+		{"cat", "bat", true},
+		{"cat", "cart", true},
+		{"cart", "cat", true},
+		{"cat", "at", true},
+		{"cat", "act", true},
+		{"cat", "cta", true},
+		{"cat", "tac", false},
+		{"cat", "dog", false},
+		{"cat", "cats!", false},
+		{"abc", "bca", false},
+		{"a", "", true},
+		{"ä", "a", true},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.a+"/"+test.b,
+			func(t *testing.T) {
+				require.Equal(t, test.similar, oneEditApart([]rune(test.a), []rune(test.b)))
+			},
+		)
+	}
+}
+
+func TestPhoneticKey(t *testing.T) {
+	type testDef struct {
+		word string
+		key  string
+	}
+
+	var tests = []testDef{
+		{"there", "0R"},
+		{"their", "0R"},
+		{"knight", "NT"},
+		{"night", "NT"},
+		{"write", "RT"},
+		{"right", "RT"},
+		{"cite", "ST"},
+		{"science", "SNS"},
+		{"phase", "FS"},
+		{"faze", "FS"},
+		{"thumb", "0M"},
+		{"edge", "EJ"},
+		{"sign", "SN"},
+		{"whole", "WL"},
+		{"xylophone", "SLFN"},
+		{"nation", "NXN"},
+		{"church", "XRX"},
+		{"school", "SKL"},
+		{"witch", "WX"},
+		{"accept", "AKSPT"},
+		{"box", "BKS"},
+		{"Café", "KF"},
+		{"yo-yo", "YY"},
+		{"ночь", ""},
+		{"", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.word,
+			func(t *testing.T) {
+				require.Equal(t, test.key, phoneticKey(test.word))
+			},
+		)
+	}
+}
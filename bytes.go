@@ -0,0 +1,180 @@
+package passgen
+
+import (
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"math/big"
+	"strings"
+)
+
+// Encoding represents the text encoding applied to random byte strings.
+type Encoding uint8
+
+// encodingNames maps each encoding to the name used to select it.
+var encodingNames = map[Encoding]string{
+	EncodingHex:             "hex",
+	EncodingBase32:          "base32",
+	EncodingBase32NoPadding: "base32-nopad",
+	EncodingBase64:          "base64",
+	EncodingBase64URL:       "base64url",
+	EncodingBase58:          "base58",
+}
+
+// alphabetBase58 is the Bitcoin base58 alphabet, which omits 0, O, I, and l.
+const alphabetBase58 = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// ParseEncoding returns the encoding with the given name, one of hex, base32, base32-nopad,
+// base64, base64url, or base58. ErrInvalidEncoding is returned for an unknown name.
+func ParseEncoding(name string) (Encoding, error) {
+	for encoding, encodingName := range encodingNames {
+		if encodingName == name {
+			return encoding, nil
+		}
+	}
+
+	return 0, ErrInvalidEncoding
+}
+
+// String returns the name of the encoding.
+func (e Encoding) String() string {
+	if name, ok := encodingNames[e]; ok {
+		return name
+	}
+
+	return "unknown"
+}
+
+// encode encodes the data as text in the encoding.
+func (e Encoding) encode(data []byte) string {
+	switch e {
+	case EncodingBase32:
+		return base32.StdEncoding.EncodeToString(data)
+	case EncodingBase32NoPadding:
+		return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(data)
+	case EncodingBase64:
+		return base64.StdEncoding.EncodeToString(data)
+	case EncodingBase64URL:
+		return base64.RawURLEncoding.EncodeToString(data)
+	case EncodingBase58:
+		return encodeBase58(data)
+	default:
+		return hex.EncodeToString(data)
+	}
+}
+
+// encodeBase58 encodes the data as a base58 number. Each leading zero byte is encoded as a leading
+// '1' so that the length of the data is preserved.
+func encodeBase58(data []byte) string {
+	var b strings.Builder
+
+	// Encode each leading zero byte as the zero digit.
+	for _, value := range data {
+		if value != 0 {
+			break
+		}
+		b.WriteByte(alphabetBase58[0])
+	}
+
+	// Encode the remaining data, writing digits from least to most significant.
+	var (
+		digits    []byte
+		remainder = new(big.Int)
+		num       = new(big.Int).SetBytes(data)
+		radix     = big.NewInt(int64(len(alphabetBase58)))
+	)
+	for num.Sign() > 0 {
+		num.DivMod(num, radix, remainder)
+		digits = append(digits, alphabetBase58[remainder.Int64()])
+	}
+
+	// Reverse the digits into most significant first order.
+	for i := len(digits) - 1; i >= 0; i-- {
+		b.WriteByte(digits[i])
+	}
+
+	return b.String()
+}
+
+// BytesOptions configures the generation of encoded random byte strings, such as HMAC keys and
+// session secrets.
+type BytesOptions struct {
+	Count    uint     // Number of byte strings to generate.
+	Size     uint     // Number of random bytes in each byte string.
+	Encoding Encoding // Text encoding applied to each byte string.
+}
+
+// DefaultBytesOptions returns byte string options populated with the package defaults.
+func DefaultBytesOptions() BytesOptions {
+	return BytesOptions{
+		Count:    BytesCountDefault,
+		Size:     BytesSizeDefault,
+		Encoding: EncodingDefault,
+	}
+}
+
+// Validate checks the options against the package limits. A *RangeError is returned for an out of
+// bounds count or size, and ErrInvalidEncoding for an unknown encoding.
+func (o BytesOptions) Validate() error {
+	// Validate the supplied count parameter.
+	if o.Count < BytesCountMin || o.Count > BytesCountMax {
+		return &RangeError{"count", BytesCountMin, BytesCountMax}
+	}
+
+	// Validate the supplied size parameter.
+	if o.Size < BytesSizeMin || o.Size > BytesSizeMax {
+		return &RangeError{"size", BytesSizeMin, BytesSizeMax}
+	}
+
+	// Validate the supplied encoding.
+	if _, ok := encodingNames[o.Encoding]; !ok {
+		return ErrInvalidEncoding
+	}
+
+	return nil
+}
+
+// Entropy returns the number of bits of entropy in each byte string generated with the options.
+// Encoding does not affect entropy, as every byte is read directly from the random source.
+func (o BytesOptions) Entropy() (float64, error) {
+	err := o.Validate()
+	if err != nil {
+		return 0, err
+	}
+
+	return float64(o.Size) * 8, nil
+}
+
+// GenerateBytes generates encoded random byte strings based on the provided options, using the
+// default Generator backed by crypto/rand.
+func GenerateBytes(opts BytesOptions) ([]string, error) {
+	return defaultGenerator.Bytes(opts)
+}
+
+// Bytes generates encoded random byte strings based on the provided options.
+func (g *Generator) Bytes(opts BytesOptions) (encoded []string, err error) {
+	// Validate the options.
+	err = opts.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		i      uint                      // Byte string counter.
+		buffer = make([]byte, opts.Size) // Byte buffer for random data.
+	)
+
+	for i = 0; i < opts.Count; i++ {
+		// Read the random data directly from the source.
+		_, err = io.ReadFull(g.source, buffer)
+		if err != nil {
+			return nil, err
+		}
+
+		// Append the encoded data to the return list.
+		encoded = append(encoded, opts.Encoding.encode(buffer))
+	}
+
+	return
+}
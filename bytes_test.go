@@ -0,0 +1,156 @@
+package passgen
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateBytes(t *testing.T) {
+	type testReqs func(t *testing.T, encoded []string, err error)
+
+	type testDef struct {
+		name    string
+		options BytesOptions
+
+		requirements testReqs
+	}
+
+	var tests = []testDef{
+		{
+			"rational defaults",
+			DefaultBytesOptions(),
+
+			func(t *testing.T, encoded []string, err error) {
+				require.NoError(t, err)
+				require.Len(t, encoded, BytesCountDefault)
+				for _, value := range encoded {
+					decoded, err := hex.DecodeString(value)
+					require.NoError(t, err)
+					require.Len(t, decoded, BytesSizeDefault)
+				}
+			},
+		},
+		{
+			"non-default parameters",
+			BytesOptions{Count: BytesCountMax, Size: BytesSizeMin, Encoding: EncodingBase64URL},
+
+			func(t *testing.T, encoded []string, err error) {
+				require.NoError(t, err)
+				require.Len(t, encoded, BytesCountMax)
+				for _, value := range encoded {
+					require.Len(t, value, 11)
+				}
+			},
+		},
+		{
+			"count too large",
+			BytesOptions{Count: BytesCountMax + 1, Size: BytesSizeDefault, Encoding: EncodingDefault},
+
+			func(t *testing.T, encoded []string, err error) {
+				require.Empty(t, encoded)
+				var rangeErr *RangeError
+				require.True(t, errors.As(err, &rangeErr))
+			},
+		},
+		{
+			"size too small",
+			BytesOptions{Count: BytesCountDefault, Size: BytesSizeMin - 1, Encoding: EncodingDefault},
+
+			func(t *testing.T, encoded []string, err error) {
+				require.Empty(t, encoded)
+				var rangeErr *RangeError
+				require.True(t, errors.As(err, &rangeErr))
+			},
+		},
+		{
+			"size too large",
+			BytesOptions{Count: BytesCountDefault, Size: BytesSizeMax + 1, Encoding: EncodingDefault},
+
+			func(t *testing.T, encoded []string, err error) {
+				require.Empty(t, encoded)
+				var rangeErr *RangeError
+				require.True(t, errors.As(err, &rangeErr))
+			},
+		},
+		{
+			"invalid encoding",
+			BytesOptions{Count: BytesCountDefault, Size: BytesSizeDefault, Encoding: EncodingBase58 + 1},
+
+			func(t *testing.T, encoded []string, err error) {
+				require.Empty(t, encoded)
+				require.True(t, errors.Is(err, ErrInvalidEncoding))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name,
+			func(t *testing.T) {
+				encoded, err := GenerateBytes(test.options)
+				test.requirements(t, encoded, err)
+			},
+		)
+	}
+
+	t.Run(
+		"random source EOF",
+		func(t *testing.T) {
+			encoded, err := NewGenerator(new(bytes.Reader)).Bytes(DefaultBytesOptions())
+			require.Empty(t, encoded)
+			require.Error(t, err)
+		},
+	)
+}
+
+func TestBytesEncodings(t *testing.T) {
+	type testDef struct {
+		name     string
+		data     []byte
+		encoding Encoding
+		expected string
+	}
+
+	var tests = []testDef{
+		{"hex", []byte("Hello World!"), EncodingHex, "48656c6c6f20576f726c6421"},
+		{"base32", []byte("Hello World!"), EncodingBase32, "JBSWY3DPEBLW64TMMQQQ===="},
+		{"base32 without padding", []byte("Hello World!"), EncodingBase32NoPadding, "JBSWY3DPEBLW64TMMQQQ"},
+		{"base64", []byte("Hello World!?"), EncodingBase64, "SGVsbG8gV29ybGQhPw=="},
+		{"base64url", []byte{0xfb, 0xff, 0xfe, 0xfb, 0xff, 0xfe, 0xfb, 0xff}, EncodingBase64URL, "-__--__--_8"},
+		{"base58", []byte("Hello World!"), EncodingBase58, "2NEpo7TZRRrLZSi2U"},
+		{"base58 leading zeros", []byte{0, 0, 0x28, 0x7f, 0xb4, 0xcd, 0, 0}, EncodingBase58, "11MAhceigo"},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name,
+			func(t *testing.T) {
+				// Read the test data as the random source of a single byte string.
+				encoded, err := NewGenerator(bytes.NewReader(test.data)).Bytes(BytesOptions{
+					Count:    1,
+					Size:     uint(len(test.data)),
+					Encoding: test.encoding,
+				})
+				require.NoError(t, err)
+				require.Equal(t, []string{test.expected}, encoded)
+
+				// Ensure the encoding name round trips.
+				parsed, err := ParseEncoding(test.encoding.String())
+				require.NoError(t, err)
+				require.Equal(t, test.encoding, parsed)
+			},
+		)
+	}
+
+	t.Run(
+		"unknown encoding name",
+		func(t *testing.T) {
+			_, err := ParseEncoding("base65")
+			require.True(t, errors.Is(err, ErrInvalidEncoding))
+		},
+	)
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/decentral1se/passgen"
+	"github.com/spf13/cobra"
+)
+
+// Names of the flags defined by alphabetConfig.
+var alphabetFlags = []string{
+	"lowercase",
+	"uppercase",
+	"numeric",
+	"special",
+	"ambiguous",
+	"alphabet",
+	"require-lowercase",
+	"require-uppercase",
+	"require-numeric",
+	"require-special",
+	"min-lowercase",
+	"min-uppercase",
+	"min-numeric",
+	"min-special",
+}
+
+// alphabetConfig converts commandline input into the alphabet and class requirements of generated
+// passwords, shared by every subcommand which draws passwords from an alphabet.
+type alphabetConfig struct {
+	alphabet string // Alphabet to use when generating passwords.
+
+	allowUppercase bool // Allow uppercase characters in passwords.
+	allowLowercase bool // Allow lowercase characters in passwords.
+	allowNumeric   bool // Allow numeric characters in passwords.
+	allowSpecial   bool // Allow special characters in passwords.
+	allowAmbiguous bool // Allow ambiguous characters in passwords.
+
+	requireUppercase bool // Require at least one uppercase character in passwords.
+	requireLowercase bool // Require at least one lowercase character in passwords.
+	requireNumeric   bool // Require at least one numeric character in passwords.
+	requireSpecial   bool // Require at least one special character in passwords.
+
+	minUppercase uint // Fewest uppercase characters allowed in passwords.
+	minLowercase uint // Fewest lowercase characters allowed in passwords.
+	minNumeric   uint // Fewest numeric characters allowed in passwords.
+	minSpecial   uint // Fewest special characters allowed in passwords.
+}
+
+// build returns the alphabet and class requirements described by the commandline input.
+func (c alphabetConfig) build() (string, []passgen.ClassRequirement) {
+	// Requiring a character class implies at least one character from it.
+	if c.requireLowercase && c.minLowercase == 0 {
+		c.minLowercase = 1
+	}
+	if c.requireUppercase && c.minUppercase == 0 {
+		c.minUppercase = 1
+	}
+	if c.requireNumeric && c.minNumeric == 0 {
+		c.minNumeric = 1
+	}
+	if c.requireSpecial && c.minSpecial == 0 {
+		c.minSpecial = 1
+	}
+
+	// Determine the characters making up each class, taking ambiguity into account.
+	var (
+		lowercase = passgen.AlphabetLower
+		uppercase = passgen.AlphabetUpper
+		numeric   = passgen.AlphabetNumeric
+		special   = passgen.AlphabetSpecial
+	)
+	if c.allowAmbiguous {
+		lowercase = passgen.AlphabetLowerAmbiguous
+		uppercase = passgen.AlphabetUpperAmbiguous
+		numeric = passgen.AlphabetNumericAmbiguous
+	}
+
+	requirements := []passgen.ClassRequirement{
+		{Characters: lowercase, Min: c.minLowercase},
+		{Characters: uppercase, Min: c.minUppercase},
+		{Characters: numeric, Min: c.minNumeric},
+		{Characters: special, Min: c.minSpecial},
+	}
+
+	// A custom alphabet supersedes the class flags.
+	if c.alphabet != "" {
+		return c.alphabet, requirements
+	}
+
+	// If the user did not specify allowance of any of lowercase, uppercase, numeric, or special
+	// characters, rely on the classes making up the default alphabet.
+	if !c.allowLowercase && !c.allowUppercase && !c.allowNumeric && !c.allowSpecial {
+		c.allowLowercase = true
+		c.allowUppercase = true
+		c.allowNumeric = true
+	}
+
+	// Instantiate a string builder for efficient alphabet construction.
+	var b strings.Builder
+
+	// Determine if the user wants passwords which include lowercase characters.
+	if c.allowLowercase || c.minLowercase > 0 {
+		b.WriteString(lowercase)
+	}
+
+	// Determine if the user wants passwords which include uppercase characters.
+	if c.allowUppercase || c.minUppercase > 0 {
+		b.WriteString(uppercase)
+	}
+
+	// Determine if the user wants passwords which include numeric characters.
+	if c.allowNumeric || c.minNumeric > 0 {
+		b.WriteString(numeric)
+	}
+
+	// Determine if the user wants passwords which include special characters.
+	if c.allowSpecial || c.minSpecial > 0 {
+		b.WriteString(special)
+	}
+
+	return b.String(), requirements
+}
+
+// addFlags defines the alphabet and class requirement flags on the command.
+func (c *alphabetConfig) addFlags(cmd *cobra.Command) {
+	// Define the flag for allowance of lowercase characters in generated passwords.
+	cmd.Flags().BoolVarP(
+		&c.allowLowercase,
+		"lowercase",
+		"l",
+		false,
+		"allow lowercase letters in passwords",
+	)
+
+	// Define the flag for allowance of uppercase characters in generated passwords.
+	cmd.Flags().BoolVarP(
+		&c.allowUppercase,
+		"uppercase",
+		"u",
+		false,
+		"allow uppercase letters in passwords",
+	)
+
+	// Define the flag for allowance of numeric characters in generated passwords.
+	cmd.Flags().BoolVarP(
+		&c.allowNumeric,
+		"numeric",
+		"n",
+		false,
+		"allow numeric characters in passwords",
+	)
+
+	// Define the flag for allowance of special characters in generated passwords.
+	cmd.Flags().BoolVarP(
+		&c.allowSpecial,
+		"special",
+		"s",
+		false,
+		"allow special characters in passwords",
+	)
+
+	// Define the flag for allowance of ambiguous characters in generated passwords.
+	cmd.Flags().BoolVarP(
+		&c.allowAmbiguous,
+		"ambiguous",
+		"a",
+		false,
+		"allow ambiguous characters in passwords",
+	)
+
+	// Define the flag for specification of a custom alphabet used by generated passwords.
+	cmd.Flags().StringVar(
+		&c.alphabet,
+		"alphabet",
+		"",
+		"alphabet to use for password generation (supersedes other flags)",
+	)
+
+	// Define the flags requiring at least one character from each class in generated passwords.
+	cmd.Flags().BoolVar(
+		&c.requireLowercase,
+		"require-lowercase",
+		false,
+		"require at least one lowercase letter in passwords",
+	)
+	cmd.Flags().BoolVar(
+		&c.requireUppercase,
+		"require-uppercase",
+		false,
+		"require at least one uppercase letter in passwords",
+	)
+	cmd.Flags().BoolVar(
+		&c.requireNumeric,
+		"require-numeric",
+		false,
+		"require at least one numeric character in passwords",
+	)
+	cmd.Flags().BoolVar(
+		&c.requireSpecial,
+		"require-special",
+		false,
+		"require at least one special character in passwords",
+	)
+
+	// Define the flags for minimum counts of each character class in generated passwords.
+	cmd.Flags().UintVar(
+		&c.minLowercase,
+		"min-lowercase",
+		0,
+		"minimum number of lowercase letters in passwords",
+	)
+	cmd.Flags().UintVar(
+		&c.minUppercase,
+		"min-uppercase",
+		0,
+		"minimum number of uppercase letters in passwords",
+	)
+	cmd.Flags().UintVar(
+		&c.minNumeric,
+		"min-numeric",
+		0,
+		"minimum number of numeric characters in passwords",
+	)
+	cmd.Flags().UintVar(
+		&c.minSpecial,
+		"min-special",
+		0,
+		"minimum number of special characters in passwords",
+	)
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/decentral1se/passgen"
+	"github.com/spf13/cobra"
+)
+
+// buildBytesCmd constructs the bytes subcommand responsible for generating encoded random bytes.
+func buildBytesCmd() *cobra.Command {
+	// Build a configuration struct for converting commandline input into parameters for a passgen
+	// GenerateBytes function call.
+	bytesConfig := struct {
+		count    uint   // Number of byte strings to generate.
+		size     uint   // Number of random bytes in each byte string.
+		encoding string // Name of the encoding applied to each byte string.
+
+		showEntropy bool // Report the entropy of generated byte strings.
+	}{
+		passgen.BytesCountDefault,
+		passgen.BytesSizeDefault,
+		passgen.EncodingDefault.String(),
+
+		false,
+	}
+
+	// Construct the command.
+	bytesCmd := &cobra.Command{
+		Use:   "bytes [size] [count]",
+		Short: "Generate encoded random bytes for keys and secrets",
+
+		Aliases: []string{
+			"key",
+		},
+
+		Args: func(cmd *cobra.Command, args []string) error {
+			// Don't allow more than two positional arguments (size and count.)
+			if len(args) > 2 {
+				return errors.New("too many args provided")
+			}
+
+			// The first argument is the number of random bytes.
+			if len(args) > 0 {
+				size, err := strconv.ParseUint(
+					args[0],
+					10,
+					64,
+				)
+				if err != nil {
+					return errors.New("invalid size provided")
+				}
+
+				// Bounds check the size for the platform.
+				if size > uint64(uintMax) {
+					return errors.New("invalid size provided")
+				}
+
+				// Update the configuration with the parsed information.
+				bytesConfig.size = uint(size)
+			}
+
+			// The second argument is the byte string count.
+			if len(args) > 1 {
+				count, err := strconv.ParseUint(
+					args[1],
+					10,
+					64,
+				)
+				if err != nil {
+					return errors.New("invalid count provided")
+				}
+
+				// Bounds check the count for the platform.
+				if count > uint64(uintMax) {
+					return errors.New("invalid count provided")
+				}
+
+				// Update the configuration with the parsed information.
+				bytesConfig.count = uint(count)
+			}
+
+			return nil
+		},
+
+		// Define what the bytes subcommand does when invoked.
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			// Look up the encoding selected by the user.
+			encoding, err := passgen.ParseEncoding(bytesConfig.encoding)
+			if err != nil {
+				return err
+			}
+
+			// Build the generation options based on the command invocation.
+			bytesOptions := passgen.BytesOptions{
+				Count:    bytesConfig.count,
+				Size:     bytesConfig.size,
+				Encoding: encoding,
+			}
+
+			// Generate byte strings based on the command invocation.
+			encoded, err := passgen.GenerateBytes(bytesOptions)
+			if err != nil {
+				return err
+			}
+
+			// Determine the entropy of the generated byte strings.
+			entropy, err := bytesOptions.Entropy()
+			if err != nil {
+				return err
+			}
+
+			// Report the entropy of the generated byte strings on stderr if requested.
+			if bytesConfig.showEntropy {
+				fmt.Fprintf(cmd.ErrOrStderr(), "entropy: %.2f bits\n", entropy)
+			}
+
+			// Write out the byte strings along with metadata describing them.
+			results := make([]result, 0, len(encoded))
+			for _, value := range encoded {
+				results = append(results, result{
+					Kind:    "bytes",
+					Value:   value,
+					Length:  uint(len(value)),
+					Entropy: entropy,
+				})
+			}
+
+			return writeResults(cmd, results)
+		},
+	}
+
+	// Define the flag for the byte string encoding.
+	bytesCmd.Flags().StringVarP(
+		&bytesConfig.encoding,
+		"encoding",
+		"e",
+		passgen.EncodingDefault.String(),
+		"encoding of random bytes (hex, base32, base32-nopad, base64, base64url, or base58)",
+	)
+
+	// Define the flag for reporting the entropy of generated byte strings.
+	bytesCmd.Flags().BoolVar(
+		&bytesConfig.showEntropy,
+		"show-entropy",
+		false,
+		"print the entropy of generated byte strings to stderr",
+	)
+
+	return bytesCmd
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/decentral1se/passgen"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBytesCommand(t *testing.T) {
+	type testReqs func(t *testing.T, output string, err error)
+
+	type testDef struct {
+		name  string
+		args  []string
+		flags map[string]string
+
+		requirements testReqs
+		setup        func() interface{}
+		teardown     func(interface{})
+	}
+
+	var tests = []testDef{
+		{
+			"rational defaults",
+			nil,
+			nil,
+
+			func(t *testing.T, output string, err error) {
+				require.NoError(t, err)
+				values := strings.Split(strings.TrimSpace(output), "\n")
+				require.Len(t, values, passgen.BytesCountDefault)
+				for _, value := range values {
+					decoded, err := hex.DecodeString(value)
+					require.NoError(t, err)
+					require.Len(t, decoded, passgen.BytesSizeDefault)
+				}
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"too many arguments",
+			[]string{"1", "2", "3"},
+			nil,
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"mistyped size argument",
+			[]string{"x"},
+			nil,
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"size argument too small",
+			[]string{strconv.FormatUint(passgen.BytesSizeMin-1, 10)},
+			nil,
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"size argument too large for platform",
+			[]string{"16"},
+			nil,
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+
+			func() interface{} {
+				originalUintMax := uintMax
+				uintMax = 15
+				return originalUintMax
+			},
+
+			func(setupContext interface{}) {
+				uintMax = setupContext.(uint)
+			},
+		},
+		{
+			"mistyped count argument",
+			[]string{
+				strconv.FormatUint(passgen.BytesSizeDefault, 10),
+				"x",
+			},
+			nil,
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"count argument too large",
+			[]string{
+				strconv.FormatUint(passgen.BytesSizeDefault, 10),
+				strconv.FormatUint(passgen.BytesCountMax+1, 10),
+			},
+			nil,
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"count argument too large for platform",
+			[]string{
+				"8",
+				"16",
+			},
+			nil,
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+
+			func() interface{} {
+				originalUintMax := uintMax
+				uintMax = 15
+				return originalUintMax
+			},
+
+			func(setupContext interface{}) {
+				uintMax = setupContext.(uint)
+			},
+		},
+		{
+			"base64url encoding",
+			[]string{"48", "4"},
+			map[string]string{
+				"encoding":     "base64url",
+				"show-entropy": "true",
+			},
+
+			func(t *testing.T, output string, err error) {
+				require.NoError(t, err)
+				values := strings.Split(strings.TrimSpace(output), "\n")
+				require.Len(t, values, 4)
+				for _, value := range values {
+					decoded, err := base64.RawURLEncoding.DecodeString(value)
+					require.NoError(t, err)
+					require.Len(t, decoded, 48)
+				}
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"unknown encoding",
+			nil,
+			map[string]string{
+				"encoding": "base65",
+			},
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+
+			nil,
+			nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name,
+			func(t *testing.T) {
+				var setupContext interface{}
+				if test.setup != nil {
+					setupContext = test.setup()
+				}
+
+				bytesCmd := buildBytesCmd()
+				var outputBuffer strings.Builder
+				bytesCmd.SetOut(&outputBuffer)
+				bytesCmd.SetErr(new(strings.Builder))
+
+				bytesCmd.SetArgs(test.args)
+				for flag, value := range test.flags {
+					err := bytesCmd.Flags().Set(flag, value)
+					require.NoError(t, err)
+				}
+
+				err := bytesCmd.Execute()
+				test.requirements(t, outputBuffer.String(), err)
+
+				if test.teardown != nil {
+					test.teardown(setupContext)
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/decentral1se/passgen"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// buildCheckCmd constructs the check subcommand responsible for checking secrets against a policy.
+func buildCheckCmd() *cobra.Command {
+	// Build a configuration struct for converting commandline input into a passgen Policy.
+	checkConfig := struct {
+		minLength uint // Fewest characters allowed in secrets, or zero for no limit.
+		maxLength uint // Most characters allowed in secrets, or zero for no limit.
+
+		minLowercase uint // Fewest lowercase letters allowed in secrets.
+		minUppercase uint // Fewest uppercase letters allowed in secrets.
+		minNumeric   uint // Fewest numeric characters allowed in secrets.
+		minSpecial   uint // Fewest special characters allowed in secrets.
+
+		forbidden   string   // Characters secrets must not contain.
+		maxRepeats  uint     // Most times a character may appear in a row, or zero for no limit.
+		maxSequence uint     // Longest run of consecutive letters or digits allowed, or zero for no limit.
+		disallowed  []string // Substrings secrets must not contain regardless of case.
+	}{
+		0,
+		0,
+
+		0,
+		0,
+		0,
+		0,
+
+		"",
+		0,
+		0,
+		nil,
+	}
+
+	// Construct the command.
+	checkCmd := &cobra.Command{
+		Use:   "check",
+		Short: "Check secrets against a policy",
+		Long: "Check secrets against a policy, printing each rule a secret violates. The secret is " +
+			"prompted for on a terminal, or otherwise every line of standard input is checked as " +
+			"a separate secret; secrets are never accepted as flags or arguments. The command fails " +
+			"if any secret violates the policy.",
+
+		Args: func(cmd *cobra.Command, args []string) error {
+			// Don't allow any positional arguments, which would expose secrets.
+			if len(args) > 0 {
+				return errors.New("secrets must be provided on stdin")
+			}
+
+			return nil
+		},
+
+		// Define what the check subcommand does when invoked.
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			// Violations are only written as text.
+			if flag := cmd.Flags().Lookup(outputFlag); flag != nil && flag.Value.String() != outputText {
+				return errors.New("check only supports text output")
+			}
+
+			// Build the policy based on the command invocation.
+			policy := passgen.Policy{
+				MinLength: checkConfig.minLength,
+				MaxLength: checkConfig.maxLength,
+
+				Requirements: []passgen.ClassRequirement{
+					{Characters: passgen.AlphabetLowerAmbiguous, Min: checkConfig.minLowercase},
+					{Characters: passgen.AlphabetUpperAmbiguous, Min: checkConfig.minUppercase},
+					{Characters: passgen.AlphabetNumericAmbiguous, Min: checkConfig.minNumeric},
+					{Characters: passgen.AlphabetSpecial, Min: checkConfig.minSpecial},
+				},
+				ForbiddenCharacters: checkConfig.forbidden,
+
+				MaxRepeats:  checkConfig.maxRepeats,
+				MaxSequence: checkConfig.maxSequence,
+
+				DisallowedSubstrings: checkConfig.disallowed,
+			}
+			err = policy.Validate()
+			if err != nil {
+				return err
+			}
+
+			// Read the secrets from the terminal or stdin.
+			secrets, err := readSecrets(cmd)
+			if err != nil {
+				return err
+			}
+
+			// A secret violating the policy is not a usage error.
+			cmd.SilenceUsage = true
+
+			// Write out every violation, numbering the secrets by the order they were read in.
+			var violating int
+			for i, secret := range secrets {
+				violations := policy.Check(secret)
+				if len(violations) == 0 {
+					continue
+				}
+				violating++
+
+				for _, violation := range violations {
+					fmt.Fprintf(cmd.OutOrStdout(), "secret %d: %s\n", i+1, violation)
+				}
+			}
+
+			if violating > 0 {
+				return fmt.Errorf("%d of %d secrets violate the policy", violating, len(secrets))
+			}
+
+			return nil
+		},
+	}
+
+	// Define the flags for the length of secrets.
+	checkCmd.Flags().UintVar(
+		&checkConfig.minLength,
+		"min-length",
+		0,
+		"fewest characters allowed in secrets (0 for no limit)",
+	)
+	checkCmd.Flags().UintVar(
+		&checkConfig.maxLength,
+		"max-length",
+		0,
+		"most characters allowed in secrets (0 for no limit)",
+	)
+
+	// Define the flags for minimum counts of each character class in secrets.
+	checkCmd.Flags().UintVar(
+		&checkConfig.minLowercase,
+		"min-lowercase",
+		0,
+		"minimum number of lowercase letters in secrets",
+	)
+	checkCmd.Flags().UintVar(
+		&checkConfig.minUppercase,
+		"min-uppercase",
+		0,
+		"minimum number of uppercase letters in secrets",
+	)
+	checkCmd.Flags().UintVar(
+		&checkConfig.minNumeric,
+		"min-numeric",
+		0,
+		"minimum number of numeric characters in secrets",
+	)
+	checkCmd.Flags().UintVar(
+		&checkConfig.minSpecial,
+		"min-special",
+		0,
+		"minimum number of special characters in secrets",
+	)
+
+	// Define the flag for characters secrets must not contain.
+	checkCmd.Flags().StringVar(
+		&checkConfig.forbidden,
+		"forbid",
+		"",
+		"characters secrets must not contain",
+	)
+
+	// Define the flags for runs of repeated and consecutive characters.
+	checkCmd.Flags().UintVar(
+		&checkConfig.maxRepeats,
+		"max-repeats",
+		0,
+		"most times a character may appear in a row (0 for no limit)",
+	)
+	checkCmd.Flags().UintVar(
+		&checkConfig.maxSequence,
+		"max-sequence",
+		0,
+		"longest run of consecutive letters or digits, such as abc or 321 (0 for no limit)",
+	)
+
+	// Define the flag for substrings secrets must not contain.
+	checkCmd.Flags().StringSliceVar(
+		&checkConfig.disallowed,
+		"disallow",
+		nil,
+		"comma-separated substrings secrets must not contain regardless of case, such as the username",
+	)
+
+	return checkCmd
+}
+
+// readSecrets reads the secrets for the check subcommand. A prompt is written to stderr and a
+// single secret is read without echo when stdin is a terminal. Otherwise every line of stdin is
+// read as a secret, excluding its line ending.
+func readSecrets(cmd *cobra.Command) ([]string, error) {
+	in := cmd.InOrStdin()
+
+	// Prompt for the secret when stdin is a terminal.
+	if isTerminal(in) {
+		fmt.Fprint(cmd.ErrOrStderr(), "secret: ")
+		secret, err := term.ReadPassword(int(in.(*os.File).Fd()))
+		fmt.Fprintln(cmd.ErrOrStderr())
+		if err != nil {
+			return nil, err
+		}
+		return []string{string(secret)}, nil
+	}
+
+	// Otherwise read every line of stdin.
+	var secrets []string
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		secrets = append(secrets, strings.TrimSuffix(scanner.Text(), "\r"))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(secrets) == 0 {
+		return nil, errors.New("no secrets provided on stdin")
+	}
+
+	return secrets, nil
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckCommand(t *testing.T) {
+	type testReqs func(t *testing.T, output string, err error)
+
+	type testDef struct {
+		name  string
+		args  []string
+		flags map[string]string
+		input string
+
+		requirements testReqs
+	}
+
+	var tests = []testDef{
+		{
+			"compliant secrets",
+			nil,
+			map[string]string{
+				"min-length":    "8",
+				"min-numeric":   "1",
+				"max-repeats":   "2",
+				"max-sequence":  "2",
+				"disallow":      "alice,example",
+				"forbid":        " ",
+				"min-lowercase": "4",
+			},
+			"correct7horse\nbattery9staple\r\n",
+
+			func(t *testing.T, output string, err error) {
+				require.NoError(t, err)
+				require.Empty(t, output)
+			},
+		},
+		{
+			"violating secrets",
+			nil,
+			map[string]string{
+				"min-length":    "8",
+				"min-uppercase": "1",
+				"max-sequence":  "2",
+				"disallow":      "alice",
+			},
+			"Correct7Horse\nalice123\nStaple\n",
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+				require.Equal(t, "2 of 3 secrets violate the policy", err.Error())
+
+				// Violations are reported without the secret.
+				require.Contains(t, output, "secret 2: required-class: ")
+				require.Contains(t, output, "secret 2: sequence: ")
+				require.Contains(t, output, "secret 2: substring: ")
+				require.Contains(t, output, "secret 3: min-length: ")
+				require.NotContains(t, output, "secret 1:")
+				require.NotContains(t, output, "alice123")
+				require.NotContains(t, output, "Staple")
+			},
+		},
+		{
+			"empty secret",
+			nil,
+			map[string]string{
+				"min-length": "1",
+			},
+			"\n",
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+				require.Contains(t, output, "secret 1: min-length: ")
+			},
+		},
+		{
+			"no secrets",
+			nil,
+			nil,
+			"",
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+		},
+		{
+			"secret as argument",
+			[]string{"hunter2"},
+			nil,
+			"",
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+		},
+		{
+			"contradictory lengths",
+			nil,
+			map[string]string{
+				"min-length": "12",
+				"max-length": "8",
+			},
+			"correct7horse\n",
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name,
+			func(t *testing.T) {
+				checkCmd := buildCheckCmd()
+				var outputBuffer strings.Builder
+				checkCmd.SetOut(&outputBuffer)
+				checkCmd.SetErr(new(strings.Builder))
+				checkCmd.SetIn(strings.NewReader(test.input))
+
+				checkCmd.SetArgs(test.args)
+				for flag, value := range test.flags {
+					err := checkCmd.Flags().Set(flag, value)
+					require.NoError(t, err)
+				}
+
+				err := checkCmd.Execute()
+				test.requirements(t, outputBuffer.String(), err)
+			},
+		)
+	}
+
+	t.Run(
+		"structured output",
+		func(t *testing.T) {
+			rootCmd := buildRootCmd()
+			rootCmd.SetOut(new(strings.Builder))
+			rootCmd.SetErr(new(strings.Builder))
+			rootCmd.SetIn(strings.NewReader("correct7horse\n"))
+			rootCmd.SetArgs([]string{"check", "--output", "json"})
+
+			require.Error(t, rootCmd.Execute())
+		},
+	)
+}
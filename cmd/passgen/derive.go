@@ -0,0 +1,183 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/decentral1se/passgen"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// buildDeriveCmd constructs the derive subcommand responsible for deterministically deriving site
+// passwords from a master secret.
+func buildDeriveCmd() *cobra.Command {
+	// Build a configuration struct for converting commandline input into parameters for a passgen
+	// DerivePassword function call.
+	deriveConfig := struct {
+		site       string // Site the password is used for.
+		length     uint   // Length of the derived password.
+		login      string // Login the password is used with.
+		counter    uint   // Counter distinguishing successive passwords for the same site and login.
+		iterations uint   // PBKDF2 iterations used to stretch the master secret.
+
+		alphabet alphabetConfig // Alphabet and class requirements of the derived password.
+	}{
+		"",
+		passgen.PasswordLengthDefault,
+		"",
+		passgen.DeriveCounterDefault,
+		passgen.DeriveIterationsDefault,
+
+		alphabetConfig{},
+	}
+
+	// Construct the command.
+	deriveCmd := &cobra.Command{
+		Use:   "derive <site> [length]",
+		Short: "Derive a reproducible site password from a master secret",
+		Long: "Derive a reproducible site password from a master secret. The same master secret, " +
+			"site, login, counter, and alphabet always derive the same password. The master " +
+			"secret is prompted for on a terminal, or otherwise read from the first line of " +
+			"standard input; it is never accepted as a flag or argument.",
+
+		Args: func(cmd *cobra.Command, args []string) error {
+			// Require the site, and allow no more than two positional arguments (site and length.)
+			if len(args) < 1 {
+				return errors.New("site must be provided")
+			}
+			if len(args) > 2 {
+				return errors.New("too many args provided")
+			}
+
+			// The first argument is the site.
+			deriveConfig.site = args[0]
+
+			// The second argument is the password length.
+			if len(args) > 1 {
+				length, err := strconv.ParseUint(
+					args[1],
+					10,
+					64,
+				)
+				if err != nil {
+					return errors.New("invalid length provided")
+				}
+
+				// Bounds check the length for the platform.
+				if length > uint64(uintMax) {
+					return errors.New("invalid length provided")
+				}
+
+				// Update the configuration with the parsed information.
+				deriveConfig.length = uint(length)
+			}
+
+			return nil
+		},
+
+		// Define what the derive subcommand does when invoked.
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			// Determine the alphabet and class requirements based on user input.
+			alphabet, requirements := deriveConfig.alphabet.build()
+
+			// Build the derivation options based on the command invocation.
+			deriveOptions := passgen.DeriveOptions{
+				Site:       deriveConfig.site,
+				Login:      deriveConfig.login,
+				Counter:    deriveConfig.counter,
+				Iterations: deriveConfig.iterations,
+
+				Length:       deriveConfig.length,
+				Alphabet:     alphabet,
+				Requirements: requirements,
+			}
+
+			// Validate the options before prompting for the master secret. The entropy of a
+			// randomly drawn password bounds that of the derived password.
+			entropy, err := deriveOptions.Entropy()
+			if err != nil {
+				return err
+			}
+
+			// Read the master secret from the terminal or stdin.
+			master, err := readMaster(cmd)
+			if err != nil {
+				return err
+			}
+
+			// Derive the password based on the command invocation.
+			password, err := passgen.DerivePassword(master, deriveOptions)
+			if err != nil {
+				return err
+			}
+
+			// Write out the password along with metadata describing it.
+			return writeResults(cmd, []result{{
+				Kind:         "derived",
+				Value:        password,
+				Length:       deriveOptions.Length,
+				AlphabetSize: passgen.PasswordOptions{Alphabet: alphabet}.AlphabetSize(),
+				Entropy:      entropy,
+			}})
+		},
+	}
+
+	// Define the flag for the login the password is used with.
+	deriveCmd.Flags().StringVar(
+		&deriveConfig.login,
+		"login",
+		"",
+		"login the password is used with, such as a username or email address",
+	)
+
+	// Define the flag for the counter used to rotate the password.
+	deriveCmd.Flags().UintVarP(
+		&deriveConfig.counter,
+		"counter",
+		"c",
+		passgen.DeriveCounterDefault,
+		"counter to increment when the password must be changed",
+	)
+
+	// Define the flag for the number of PBKDF2 iterations.
+	deriveCmd.Flags().UintVar(
+		&deriveConfig.iterations,
+		"iterations",
+		passgen.DeriveIterationsDefault,
+		"PBKDF2 iterations used to stretch the master secret",
+	)
+
+	// Define the flags for the alphabet and class requirements of the derived password.
+	deriveConfig.alphabet.addFlags(deriveCmd)
+
+	return deriveCmd
+}
+
+// readMaster reads the master secret for the derive subcommand. A prompt is written to stderr and
+// the secret is read without echo when stdin is a terminal. Otherwise the first line of stdin is
+// read, excluding its line ending.
+func readMaster(cmd *cobra.Command) ([]byte, error) {
+	in := cmd.InOrStdin()
+
+	// Prompt for the secret when stdin is a terminal.
+	if isTerminal(in) {
+		fmt.Fprint(cmd.ErrOrStderr(), "master secret: ")
+		master, err := term.ReadPassword(int(in.(*os.File).Fd()))
+		fmt.Fprintln(cmd.ErrOrStderr())
+		return master, err
+	}
+
+	// Otherwise read the first line of stdin.
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return []byte(strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")), nil
+}
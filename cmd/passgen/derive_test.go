@@ -0,0 +1,232 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/decentral1se/passgen"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeriveCommand(t *testing.T) {
+	// Derive the expected password for the default options using the library directly.
+	opts := passgen.DefaultDeriveOptions("example.com", "user")
+	opts.Iterations = passgen.DeriveIterationsMin
+	expected, err := passgen.DerivePassword([]byte("master secret"), opts)
+	require.NoError(t, err)
+
+	type testReqs func(t *testing.T, output string, err error)
+
+	type testDef struct {
+		name  string
+		args  []string
+		flags map[string]string
+		input string
+
+		requirements testReqs
+		setup        func() interface{}
+		teardown     func(interface{})
+	}
+
+	var tests = []testDef{
+		{
+			"matches library derivation",
+			[]string{"example.com"},
+			map[string]string{
+				"login":      "user",
+				"iterations": strconv.FormatUint(passgen.DeriveIterationsMin, 10),
+			},
+			"master secret\n",
+
+			func(t *testing.T, output string, err error) {
+				require.NoError(t, err)
+				require.Equal(t, expected+"\n", output)
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"windows line ending",
+			[]string{"example.com"},
+			map[string]string{
+				"login":      "user",
+				"iterations": strconv.FormatUint(passgen.DeriveIterationsMin, 10),
+			},
+			"master secret\r\nignored\n",
+
+			func(t *testing.T, output string, err error) {
+				require.NoError(t, err)
+				require.Equal(t, expected+"\n", output)
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"incremented counter",
+			[]string{"example.com"},
+			map[string]string{
+				"login":      "user",
+				"counter":    "2",
+				"iterations": strconv.FormatUint(passgen.DeriveIterationsMin, 10),
+			},
+			"master secret",
+
+			func(t *testing.T, output string, err error) {
+				require.NoError(t, err)
+				require.NotEqual(t, expected+"\n", output)
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"class requirements",
+			[]string{"example.com", "8"},
+			map[string]string{
+				"iterations":  strconv.FormatUint(passgen.DeriveIterationsMin, 10),
+				"special":     "true",
+				"min-special": "3",
+			},
+			"master secret",
+
+			func(t *testing.T, output string, err error) {
+				require.NoError(t, err)
+				password := strings.TrimSpace(output)
+				require.Len(t, password, 8)
+				var special int
+				for _, char := range password {
+					if strings.ContainsRune(passgen.AlphabetSpecial, char) {
+						special++
+					}
+				}
+				require.GreaterOrEqual(t, special, 3)
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"empty master secret",
+			[]string{"example.com"},
+			map[string]string{
+				"iterations": strconv.FormatUint(passgen.DeriveIterationsMin, 10),
+			},
+			"\n",
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"missing site",
+			nil,
+			nil,
+			"master secret",
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"too many arguments",
+			[]string{"example.com", "16", "1"},
+			nil,
+			"master secret",
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"mistyped length argument",
+			[]string{"example.com", "x"},
+			nil,
+			"master secret",
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"length argument too large for platform",
+			[]string{"example.com", "16"},
+			nil,
+			"master secret",
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+
+			func() interface{} {
+				originalUintMax := uintMax
+				uintMax = 15
+				return originalUintMax
+			},
+
+			func(setupContext interface{}) {
+				uintMax = setupContext.(uint)
+			},
+		},
+		{
+			"too few iterations",
+			[]string{"example.com"},
+			map[string]string{
+				"iterations": strconv.FormatUint(passgen.DeriveIterationsMin-1, 10),
+			},
+			"master secret",
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+
+			nil,
+			nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name,
+			func(t *testing.T) {
+				var setupContext interface{}
+				if test.setup != nil {
+					setupContext = test.setup()
+				}
+
+				deriveCmd := buildDeriveCmd()
+				var outputBuffer strings.Builder
+				deriveCmd.SetOut(&outputBuffer)
+				deriveCmd.SetErr(new(strings.Builder))
+				deriveCmd.SetIn(strings.NewReader(test.input))
+
+				deriveCmd.SetArgs(test.args)
+				for flag, value := range test.flags {
+					err := deriveCmd.Flags().Set(flag, value)
+					require.NoError(t, err)
+				}
+
+				err := deriveCmd.Execute()
+				test.requirements(t, outputBuffer.String(), err)
+
+				if test.teardown != nil {
+					test.teardown(setupContext)
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/decentral1se/passgen"
+	"github.com/spf13/cobra"
+)
+
+// buildListsCmd constructs the lists subcommand responsible for describing the built-in word lists.
+func buildListsCmd() *cobra.Command {
+	// Construct the command.
+	listsCmd := &cobra.Command{
+		Use:   "lists",
+		Short: "Show the built-in word lists",
+
+		Args: func(cmd *cobra.Command, args []string) error {
+			// Don't allow any positional arguments.
+			if len(args) > 0 {
+				return errors.New("too many args provided")
+			}
+
+			return nil
+		},
+
+		// Define what the lists subcommand does when invoked.
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			// Describe each list as a table when writing text output.
+			if flag := cmd.Flags().Lookup(outputFlag); flag == nil || flag.Value.String() == outputText {
+				w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+				fmt.Fprintln(w, "NAME\tWORDS\tBITS/WORD\tDESCRIPTION")
+				for _, list := range passgen.WordLists {
+					fmt.Fprintf(w, "%s\t%d\t%.2f\t%s\n", list.Name, list.Size(), list.BitsPerWord(), list.Description)
+				}
+
+				return w.Flush()
+			}
+
+			// Otherwise write out each list name along with metadata describing the list.
+			results := make([]result, 0, len(passgen.WordLists))
+			for _, list := range passgen.WordLists {
+				results = append(results, result{
+					Kind:         "word-list",
+					Value:        list.Name,
+					WordListSize: list.Size(),
+					Entropy:      list.BitsPerWord(),
+				})
+			}
+
+			return writeResults(cmd, results)
+		},
+	}
+
+	// Construct the word list check subcommand.
+	checkCmd := buildListsCheckCmd()
+	listsCmd.AddCommand(checkCmd)
+
+	return listsCmd
+}
+
+// buildListsCheckCmd constructs the lists check subcommand responsible for reporting issues with a
+// word list file.
+func buildListsCheckCmd() *cobra.Command {
+	// Most issues of each kind to show, where zero shows every issue.
+	var limit uint
+
+	// Construct the command.
+	checkCmd := &cobra.Command{
+		Use:   "check <file>",
+		Short: "Check a word list for words which may be confused or run together",
+		Long: "Check a word list for words which may be confused or run together. Words which are " +
+			"a prefix of another word, pairs of words which join to the same string as a different " +
+			"pair, words one edit apart, and words which may sound alike are reported, along with " +
+			"the number of unique words and the bits of entropy each contributes to a passphrase. " +
+			"Words sound alike when they share a Metaphone key, which suits English words and " +
+			"also pairs some words which merely share their consonants. A prefix-free list is " +
+			"safe to join without separators.",
+
+		Args: func(cmd *cobra.Command, args []string) error {
+			// Require exactly one positional argument (the word list file.)
+			if len(args) < 1 {
+				return errors.New("word list file must be provided")
+			}
+			if len(args) > 1 {
+				return errors.New("too many args provided")
+			}
+
+			return nil
+		},
+
+		// Define what the check subcommand does when invoked.
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			// Read the word list file.
+			wordListFile, err := os.Open(args[0])
+			if err != nil {
+				return err
+			}
+			defer func() {
+				_ = wordListFile.Close()
+			}()
+
+			wordList, err := passgen.LoadWordList(wordListFile)
+			if err != nil {
+				return fmt.Errorf("%s: %w", args[0], err)
+			}
+
+			analysis := passgen.AnalyzeWordList(wordList)
+
+			// Write out only the summary of the list when writing structured output.
+			if flag := cmd.Flags().Lookup(outputFlag); flag != nil && flag.Value.String() != outputText {
+				return writeResults(cmd, []result{{
+					Kind:         "word-list",
+					Value:        args[0],
+					WordListSize: analysis.UniqueWords,
+					Entropy:      analysis.BitsPerWord,
+				}})
+			}
+
+			// Otherwise write out the summary followed by each kind of issue.
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintf(w, "words\t%d\n", analysis.WordCount)
+			fmt.Fprintf(w, "unique words\t%d\n", analysis.UniqueWords)
+			fmt.Fprintf(w, "bits per word\t%.2f\n", analysis.BitsPerWord)
+			fmt.Fprintf(w, "prefix-free\t%t\n", analysis.PrefixFree())
+
+			fmt.Fprintf(w, "\nprefix words: %d\n", len(analysis.PrefixWords))
+			for i, pair := range analysis.PrefixWords {
+				if !writeIssue(w, i, len(analysis.PrefixWords), limit) {
+					break
+				}
+				fmt.Fprintf(w, "  %s\t%s\n", pair.First, pair.Second)
+			}
+
+			fmt.Fprintf(w, "\nambiguous concatenations: %d\n", len(analysis.AmbiguousConcatenations))
+			for i, ambiguous := range analysis.AmbiguousConcatenations {
+				if !writeIssue(w, i, len(analysis.AmbiguousConcatenations), limit) {
+					break
+				}
+				fmt.Fprintf(
+					w,
+					"  %s\t%s %s\t%s %s\n",
+					ambiguous.Joined,
+					ambiguous.First.First,
+					ambiguous.First.Second,
+					ambiguous.Second.First,
+					ambiguous.Second.Second,
+				)
+			}
+
+			fmt.Fprintf(w, "\nsimilar words: %d\n", len(analysis.SimilarWords))
+			for i, pair := range analysis.SimilarWords {
+				if !writeIssue(w, i, len(analysis.SimilarWords), limit) {
+					break
+				}
+				fmt.Fprintf(w, "  %s\t%s\n", pair.First, pair.Second)
+			}
+
+			fmt.Fprintf(w, "\nsound-alike words: %d\n", len(analysis.SoundAlikeWords))
+			for i, pair := range analysis.SoundAlikeWords {
+				if !writeIssue(w, i, len(analysis.SoundAlikeWords), limit) {
+					break
+				}
+				fmt.Fprintf(w, "  %s\t%s\n", pair.First, pair.Second)
+			}
+
+			return w.Flush()
+		},
+	}
+
+	// Define the flag limiting the issues shown of each kind.
+	checkCmd.Flags().UintVarP(
+		&limit,
+		"limit",
+		"l",
+		10,
+		"most issues of each kind to show, or 0 to show every issue",
+	)
+
+	return checkCmd
+}
+
+// writeIssue reports whether the issue at index i of count issues is within the limit, writing a
+// note of how many issues remain to w when it is the first issue beyond the limit.
+func writeIssue(w io.Writer, i int, count int, limit uint) bool {
+	if limit == 0 || uint(i) < limit {
+		return true
+	}
+
+	fmt.Fprintf(w, "  ... and %d more\n", count-i)
+	return false
+}
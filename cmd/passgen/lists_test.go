@@ -0,0 +1,232 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/decentral1se/passgen"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListsCommand(t *testing.T) {
+	type testReqs func(t *testing.T, output string, err error)
+
+	type testDef struct {
+		name string
+		args []string
+
+		requirements testReqs
+	}
+
+	var tests = []testDef{
+		{
+			"table of lists",
+			nil,
+
+			func(t *testing.T, output string, err error) {
+				require.NoError(t, err)
+				lines := strings.Split(strings.TrimSpace(output), "\n")
+				require.Len(t, lines, len(passgen.WordLists)+1)
+				require.Equal(t, []string{"NAME", "WORDS", "BITS/WORD", "DESCRIPTION"}, strings.Fields(lines[0]))
+				require.Equal(t, []string{"eff-large", "7776", "12.92"}, strings.Fields(lines[1])[:3])
+				require.Equal(t, []string{"eff-short-1", "1296", "10.34"}, strings.Fields(lines[2])[:3])
+				require.Equal(t, []string{"eff-short-2", "1296", "10.34"}, strings.Fields(lines[3])[:3])
+			},
+		},
+		{
+			"too many arguments",
+			[]string{"x"},
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name,
+			func(t *testing.T) {
+				listsCmd := buildListsCmd()
+				var outputBuffer strings.Builder
+				listsCmd.SetOut(&outputBuffer)
+				listsCmd.SetErr(new(strings.Builder))
+				listsCmd.SetArgs(test.args)
+
+				err := listsCmd.Execute()
+				test.requirements(t, outputBuffer.String(), err)
+			},
+		)
+	}
+
+	t.Run(
+		"json output",
+		func(t *testing.T) {
+			rootCmd := buildRootCmd()
+			var outputBuffer strings.Builder
+			rootCmd.SetOut(&outputBuffer)
+			rootCmd.SetArgs([]string{"lists", "--output", "json"})
+			require.NoError(t, rootCmd.Execute())
+
+			var results []result
+			require.NoError(t, json.Unmarshal([]byte(outputBuffer.String()), &results))
+			require.Len(t, results, len(passgen.WordLists))
+			require.Equal(t, "bip39-english", results[4].Value)
+			require.EqualValues(t, 2048, results[4].WordListSize)
+			require.Equal(t, 11.0, results[4].Entropy)
+		},
+	)
+}
+
+func TestListsCheckCommand(t *testing.T) {
+	type testReqs func(t *testing.T, output string, err error)
+
+	type testDef struct {
+		name string
+		args []string
+
+		requirements testReqs
+	}
+
+	// Write a word list with a prefix word, an ambiguous concatenation, and similar words which sound
+	// alike to a temp file.
+	wordListFile, err := ioutil.TempFile("", "")
+
+	defer func() {
+		_ = wordListFile.Close()
+	}()
+
+	require.NoError(t, err)
+	wordListFilename := wordListFile.Name()
+	_, err = wordListFile.WriteString("# Example list\ncan\ncanal\nalarm\narm\nform\nfrom\nform\n")
+	require.NoError(t, err)
+
+	var tests = []testDef{
+		{
+			"report",
+			[]string{"check", wordListFilename},
+
+			func(t *testing.T, output string, err error) {
+				require.NoError(t, err)
+				require.Equal(
+					t,
+					[]string{
+						"words          7",
+						"unique words   6",
+						"bits per word  2.58",
+						"prefix-free    false",
+						"",
+						"prefix words: 1",
+						"  can  canal",
+						"",
+						"ambiguous concatenations: 1",
+						"  canalarm  can alarm  canal arm",
+						"",
+						"similar words: 1",
+						"  form  from",
+						"",
+						"sound-alike words: 1",
+						"  form  from",
+					},
+					strings.Split(strings.TrimSpace(output), "\n"),
+				)
+			},
+		},
+		{
+			"unlimited report",
+			[]string{"check", "--limit", "0", wordListFilename},
+
+			func(t *testing.T, output string, err error) {
+				require.NoError(t, err)
+				require.NotContains(t, output, "more")
+			},
+		},
+		{
+			"nonexistent file",
+			[]string{"check", "fake.txt"},
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+		},
+		{
+			"missing file argument",
+			[]string{"check"},
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+		},
+		{
+			"too many arguments",
+			[]string{"check", wordListFilename, "x"},
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name,
+			func(t *testing.T) {
+				listsCmd := buildListsCmd()
+				var outputBuffer strings.Builder
+				listsCmd.SetOut(&outputBuffer)
+				listsCmd.SetErr(new(strings.Builder))
+				listsCmd.SetArgs(test.args)
+
+				err := listsCmd.Execute()
+				test.requirements(t, outputBuffer.String(), err)
+			},
+		)
+	}
+
+	t.Run(
+		"limit",
+		func(t *testing.T) {
+			listsCmd := buildListsCmd()
+			var outputBuffer strings.Builder
+			listsCmd.SetOut(&outputBuffer)
+			listsCmd.SetArgs([]string{"check", "--limit", "1", wordListFilename})
+			require.NoError(t, listsCmd.Execute())
+			require.NotContains(t, outputBuffer.String(), "more")
+
+			// Every similar pair of the built-in BIP39 list exceeds the limit but the first.
+			bip39File, err := ioutil.TempFile("", "")
+			require.NoError(t, err)
+			defer func() {
+				_ = bip39File.Close()
+			}()
+			_, err = bip39File.WriteString(strings.Join(passgen.WordListBIP39English, "\n"))
+			require.NoError(t, err)
+
+			outputBuffer.Reset()
+			listsCmd.SetArgs([]string{"check", "--limit", "1", bip39File.Name()})
+			require.NoError(t, listsCmd.Execute())
+			similar := passgen.AnalyzeWordList(passgen.WordListBIP39English).SimilarWords
+			require.Contains(t, outputBuffer.String(), fmt.Sprintf("  ... and %d more\n", len(similar)-1))
+		},
+	)
+
+	t.Run(
+		"json output",
+		func(t *testing.T) {
+			rootCmd := buildRootCmd()
+			var outputBuffer strings.Builder
+			rootCmd.SetOut(&outputBuffer)
+			rootCmd.SetArgs([]string{"lists", "check", wordListFilename, "--output", "json"})
+			require.NoError(t, rootCmd.Execute())
+
+			var results []result
+			require.NoError(t, json.Unmarshal([]byte(outputBuffer.String()), &results))
+			require.Len(t, results, 1)
+			require.Equal(t, wordListFilename, results[0].Value)
+			require.EqualValues(t, 6, results[0].WordListSize)
+		},
+	)
+}
@@ -17,14 +17,23 @@ var (
 	version string
 )
 
-func main() {
+// buildRootCmd constructs the root command along with each of its subcommands.
+func buildRootCmd() *cobra.Command {
 	// Define the root command.
 	rootCmd := &cobra.Command{
 		Use:     "passgen",
-		Short:   "Generate passwords and passphrases",
+		Short:   "Generate passwords, passphrases, and other secrets",
 		Version: version,
 	}
 
+	// Define the flag for the output format shared by every subcommand.
+	rootCmd.PersistentFlags().StringP(
+		outputFlag,
+		"o",
+		outputText,
+		"output format (text, json, jsonl, csv, or nul)",
+	)
+
 	// Construct the password generation subcommand.
 	passwordCmd := buildPasswordCmd()
 	rootCmd.AddCommand(passwordCmd)
@@ -33,8 +42,40 @@ func main() {
 	passphraseCmd := buildPassphraseCmd()
 	rootCmd.AddCommand(passphraseCmd)
 
+	// Construct the PIN generation subcommand.
+	pinCmd := buildPINCmd()
+	rootCmd.AddCommand(pinCmd)
+
+	// Construct the token generation subcommand.
+	tokenCmd := buildTokenCmd()
+	rootCmd.AddCommand(tokenCmd)
+
+	// Construct the random bytes generation subcommand.
+	bytesCmd := buildBytesCmd()
+	rootCmd.AddCommand(bytesCmd)
+
+	// Construct the mnemonic generation subcommand.
+	mnemonicCmd := buildMnemonicCmd()
+	rootCmd.AddCommand(mnemonicCmd)
+
+	// Construct the password derivation subcommand.
+	deriveCmd := buildDeriveCmd()
+	rootCmd.AddCommand(deriveCmd)
+
+	// Construct the policy check subcommand.
+	checkCmd := buildCheckCmd()
+	rootCmd.AddCommand(checkCmd)
+
+	// Construct the word list description subcommand.
+	listsCmd := buildListsCmd()
+	rootCmd.AddCommand(listsCmd)
+
+	return rootCmd
+}
+
+func main() {
 	// Run the root command.
-	err := rootCmd.Execute()
+	err := buildRootCmd().Execute()
 	if err != nil {
 		exitFunc(2)
 	}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/decentral1se/passgen"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// buildMnemonicCmd constructs the mnemonic subcommand responsible for generating BIP39 mnemonics.
+func buildMnemonicCmd() *cobra.Command {
+	// Build a configuration struct for converting commandline input into parameters for a passgen
+	// GenerateMnemonics function call.
+	mnemonicConfig := struct {
+		count       uint // Number of mnemonics to generate.
+		entropyBits uint // Bits of entropy encoded in each mnemonic.
+
+		showEntropy bool // Report the entropy of generated mnemonics.
+	}{
+		passgen.MnemonicCountDefault,
+		passgen.MnemonicEntropyBitsDefault,
+
+		false,
+	}
+
+	// Construct the command.
+	mnemonicCmd := &cobra.Command{
+		Use:   "mnemonic [count]",
+		Short: "Generate BIP39 mnemonics with a checksum",
+
+		Aliases: []string{
+			"bip39",
+		},
+
+		Args: func(cmd *cobra.Command, args []string) error {
+			// Don't allow more than one positional argument (count.)
+			if len(args) > 1 {
+				return errors.New("too many args provided")
+			}
+
+			// The first argument is the mnemonic count.
+			if len(args) > 0 {
+				count, err := strconv.ParseUint(
+					args[0],
+					10,
+					64,
+				)
+				if err != nil {
+					return errors.New("invalid count provided")
+				}
+
+				// Bounds check the count for the platform.
+				if count > uint64(uintMax) {
+					return errors.New("invalid count provided")
+				}
+
+				// Update the configuration with the parsed information.
+				mnemonicConfig.count = uint(count)
+			}
+
+			return nil
+		},
+
+		// Define what the mnemonic subcommand does when invoked.
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			// Build the generation options based on the command invocation.
+			mnemonicOptions := passgen.MnemonicOptions{
+				Count:       mnemonicConfig.count,
+				EntropyBits: mnemonicConfig.entropyBits,
+				WordList:    passgen.WordListBIP39English,
+			}
+
+			// Generate mnemonics based on the command invocation.
+			mnemonics, err := passgen.GenerateMnemonics(mnemonicOptions)
+			if err != nil {
+				return err
+			}
+
+			// Determine the entropy of the generated mnemonics.
+			entropy, err := mnemonicOptions.Entropy()
+			if err != nil {
+				return err
+			}
+
+			// Report the entropy of the generated mnemonics on stderr if requested.
+			if mnemonicConfig.showEntropy {
+				fmt.Fprintf(cmd.ErrOrStderr(), "entropy: %.2f bits\n", entropy)
+			}
+
+			// Write out the mnemonics along with metadata describing them.
+			results := make([]result, 0, len(mnemonics))
+			for _, mnemonic := range mnemonics {
+				results = append(results, result{
+					Kind:         "mnemonic",
+					Value:        mnemonic,
+					WordCount:    mnemonicOptions.WordCount(),
+					WordListSize: passgen.MnemonicWordListLength,
+					Entropy:      entropy,
+				})
+			}
+
+			return writeResults(cmd, results)
+		},
+	}
+
+	// Define the flag for the entropy encoded in each mnemonic.
+	mnemonicCmd.Flags().UintVarP(
+		&mnemonicConfig.entropyBits,
+		"bits",
+		"b",
+		passgen.MnemonicEntropyBitsDefault,
+		"bits of entropy in each mnemonic (128, 160, 192, 224, or 256)",
+	)
+
+	// Define the flag for reporting the entropy of generated mnemonics.
+	mnemonicCmd.Flags().BoolVar(
+		&mnemonicConfig.showEntropy,
+		"show-entropy",
+		false,
+		"print the entropy of generated mnemonics to stderr",
+	)
+
+	// Construct the mnemonic verification subcommand.
+	verifyCmd := buildMnemonicVerifyCmd()
+	mnemonicCmd.AddCommand(verifyCmd)
+
+	return mnemonicCmd
+}
+
+// buildMnemonicVerifyCmd constructs the mnemonic verify subcommand responsible for checking a
+// mnemonic and recovering the entropy it encodes.
+func buildMnemonicVerifyCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "verify",
+		Short: "Verify a mnemonic's checksum and print its entropy in hex",
+		Long: "Verify a mnemonic's checksum and print its entropy in hex. The mnemonic is prompted " +
+			"for on a terminal, or otherwise read from standard input; it is never accepted as an " +
+			"argument, which would expose it in the shell history and process list.",
+
+		Args: func(cmd *cobra.Command, args []string) error {
+			// Don't allow any positional arguments, which would expose the mnemonic.
+			if len(args) > 0 {
+				return errors.New("mnemonic must be provided on stdin")
+			}
+
+			return nil
+		},
+
+		// Define what the verify subcommand does when invoked.
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			// Read the mnemonic from the terminal or stdin.
+			mnemonic, err := readMnemonic(cmd)
+			if err != nil {
+				return err
+			}
+
+			// Decode the mnemonic, ensuring its checksum matches.
+			entropy, err := passgen.DecodeMnemonic(mnemonic, passgen.WordListBIP39English)
+			if err != nil {
+				return err
+			}
+
+			// Write out the entropy encoded by the mnemonic.
+			return writeResults(cmd, []result{{
+				Kind:    "entropy",
+				Value:   hex.EncodeToString(entropy),
+				Length:  uint(len(entropy)) * 2,
+				Entropy: float64(len(entropy)) * 8,
+			}})
+		},
+	}
+}
+
+// readMnemonic reads the mnemonic for the verify subcommand. A prompt is written to stderr and the
+// mnemonic is read without echo when stdin is a terminal. Otherwise all of stdin is read.
+func readMnemonic(cmd *cobra.Command) (string, error) {
+	in := cmd.InOrStdin()
+
+	// Prompt for the mnemonic when stdin is a terminal.
+	if isTerminal(in) {
+		fmt.Fprint(cmd.ErrOrStderr(), "mnemonic: ")
+		mnemonic, err := term.ReadPassword(int(in.(*os.File).Fd()))
+		fmt.Fprintln(cmd.ErrOrStderr())
+		return string(mnemonic), err
+	}
+
+	// Otherwise read all of stdin.
+	input, err := io.ReadAll(in)
+	return string(input), err
+}
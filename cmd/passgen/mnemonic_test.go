@@ -0,0 +1,234 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/decentral1se/passgen"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMnemonicCommand(t *testing.T) {
+	type testReqs func(t *testing.T, output string, err error)
+
+	type testDef struct {
+		name  string
+		args  []string
+		flags map[string]string
+
+		requirements testReqs
+		setup        func() interface{}
+		teardown     func(interface{})
+	}
+
+	var tests = []testDef{
+		{
+			"rational defaults",
+			nil,
+			nil,
+
+			func(t *testing.T, output string, err error) {
+				require.NoError(t, err)
+				mnemonics := strings.Split(strings.TrimSpace(output), "\n")
+				require.Len(t, mnemonics, passgen.MnemonicCountDefault)
+				for _, mnemonic := range mnemonics {
+					require.Len(t, strings.Fields(mnemonic), 12)
+					require.NoError(t, passgen.VerifyMnemonic(mnemonic, passgen.WordListBIP39English))
+				}
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"too many arguments",
+			[]string{"1", "2"},
+			nil,
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"mistyped count argument",
+			[]string{"x"},
+			nil,
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"count argument too large",
+			[]string{strconv.FormatUint(passgen.MnemonicCountMax+1, 10)},
+			nil,
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"count argument too large for platform",
+			[]string{"16"},
+			nil,
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+
+			func() interface{} {
+				originalUintMax := uintMax
+				uintMax = 15
+				return originalUintMax
+			},
+
+			func(setupContext interface{}) {
+				uintMax = setupContext.(uint)
+			},
+		},
+		{
+			"maximum entropy",
+			[]string{"4"},
+			map[string]string{
+				"bits":         strconv.FormatUint(passgen.MnemonicEntropyBitsMax, 10),
+				"show-entropy": "true",
+			},
+
+			func(t *testing.T, output string, err error) {
+				require.NoError(t, err)
+				mnemonics := strings.Split(strings.TrimSpace(output), "\n")
+				require.Len(t, mnemonics, 4)
+				for _, mnemonic := range mnemonics {
+					require.Len(t, strings.Fields(mnemonic), 24)
+				}
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"unsupported entropy",
+			nil,
+			map[string]string{
+				"bits": "100",
+			},
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+
+			nil,
+			nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name,
+			func(t *testing.T) {
+				var setupContext interface{}
+				if test.setup != nil {
+					setupContext = test.setup()
+				}
+
+				mnemonicCmd := buildMnemonicCmd()
+				var outputBuffer strings.Builder
+				mnemonicCmd.SetOut(&outputBuffer)
+				mnemonicCmd.SetErr(new(strings.Builder))
+
+				mnemonicCmd.SetArgs(test.args)
+				for flag, value := range test.flags {
+					err := mnemonicCmd.Flags().Set(flag, value)
+					require.NoError(t, err)
+				}
+
+				err := mnemonicCmd.Execute()
+				test.requirements(t, outputBuffer.String(), err)
+
+				if test.teardown != nil {
+					test.teardown(setupContext)
+				}
+			},
+		)
+	}
+}
+
+func TestMnemonicVerifyCommand(t *testing.T) {
+	type testDef struct {
+		name   string
+		args   []string
+		input  string
+		output string
+		fails  bool
+	}
+
+	var tests = []testDef{
+		{
+			"mnemonic across lines",
+			nil,
+			"legal winner thank year wave sausage\nworth useful legal winner thank yellow\n",
+			"7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f\n",
+			false,
+		},
+		{
+			"mnemonic as arguments",
+			strings.Fields("legal winner thank year wave sausage worth useful legal winner thank yellow"),
+			"",
+			"",
+			true,
+		},
+		{
+			"mnemonic on stdin",
+			nil,
+			"letter advice cage absurd amount doctor acoustic avoid letter advice cage above\n",
+			"80808080808080808080808080808080\n",
+			false,
+		},
+		{
+			"checksum mismatch",
+			nil,
+			"legal winner thank year wave sausage worth useful legal winner thank zoo\n",
+			"",
+			true,
+		},
+		{
+			"empty stdin",
+			nil,
+			"",
+			"",
+			true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name,
+			func(t *testing.T) {
+				verifyCmd := buildMnemonicVerifyCmd()
+				var outputBuffer strings.Builder
+				verifyCmd.SetOut(&outputBuffer)
+				verifyCmd.SetErr(new(strings.Builder))
+				verifyCmd.SetIn(strings.NewReader(test.input))
+				verifyCmd.SetArgs(test.args)
+
+				err := verifyCmd.Execute()
+				if test.fails {
+					require.Error(t, err)
+				} else {
+					require.NoError(t, err)
+					require.Equal(t, test.output, outputBuffer.String())
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+// Name of the persistent flag selecting the output format.
+const outputFlag = "output"
+
+// Supported output formats.
+const (
+	outputText  = "text"  // One secret per line.
+	outputJSON  = "json"  // A JSON array of results.
+	outputJSONL = "jsonl" // One JSON result per line.
+	outputCSV   = "csv"   // CSV results with a header row.
+	outputNUL   = "nul"   // Secrets terminated by NUL characters.
+)
+
+// result describes a generated secret along with metadata about how it was generated.
+type result struct {
+	Kind         string  `json:"kind"`                     // Kind of secret, e.g. password or passphrase.
+	Value        string  `json:"value"`                    // The generated secret.
+	Length       uint    `json:"length,omitempty"`         // Length, in characters, of the secret.
+	WordCount    uint    `json:"word_count,omitempty"`     // Length, in words, of the secret.
+	AlphabetSize uint    `json:"alphabet_size,omitempty"`  // Unique characters the secret was drawn from.
+	WordListSize uint    `json:"word_list_size,omitempty"` // Unique words the secret was drawn from.
+	Entropy      float64 `json:"entropy"`                  // Bits of entropy in the secret.
+}
+
+// writeResults writes the results to the command output in the format selected by the output
+// flag, defaulting to text when the flag is not defined.
+func writeResults(cmd *cobra.Command, results []result) (err error) {
+	format := outputText
+	if flag := cmd.Flags().Lookup(outputFlag); flag != nil {
+		format = flag.Value.String()
+	}
+
+	out := cmd.OutOrStdout()
+
+	switch format {
+	case outputText:
+		// Print out a single secret per line.
+		for _, r := range results {
+			fmt.Fprintln(out, r.Value)
+		}
+
+	case outputNUL:
+		// Terminate each secret with a NUL character, which cannot appear within a secret.
+		for _, r := range results {
+			fmt.Fprint(out, r.Value+"\x00")
+		}
+
+	case outputJSON:
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(results)
+		if err != nil {
+			return err
+		}
+
+	case outputJSONL:
+		encoder := json.NewEncoder(out)
+		for _, r := range results {
+			err = encoder.Encode(r)
+			if err != nil {
+				return err
+			}
+		}
+
+	case outputCSV:
+		writer := csv.NewWriter(out)
+		err = writer.Write([]string{
+			"kind",
+			"value",
+			"length",
+			"word_count",
+			"alphabet_size",
+			"word_list_size",
+			"entropy",
+		})
+		if err != nil {
+			return err
+		}
+		for _, r := range results {
+			err = writer.Write([]string{
+				r.Kind,
+				r.Value,
+				strconv.FormatUint(uint64(r.Length), 10),
+				strconv.FormatUint(uint64(r.WordCount), 10),
+				strconv.FormatUint(uint64(r.AlphabetSize), 10),
+				strconv.FormatUint(uint64(r.WordListSize), 10),
+				strconv.FormatFloat(r.Entropy, 'f', -1, 64),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+
+	default:
+		return fmt.Errorf("invalid output format %q", format)
+	}
+
+	return
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/decentral1se/passgen"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutputFormats(t *testing.T) {
+	type testReqs func(t *testing.T, output string, err error)
+
+	type testDef struct {
+		name string
+		args []string
+
+		requirements testReqs
+	}
+
+	var tests = []testDef{
+		{
+			"text output",
+			[]string{"password", "--output", "text", "16", "3"},
+
+			func(t *testing.T, output string, err error) {
+				require.NoError(t, err)
+				require.Len(t, strings.Split(strings.TrimSpace(output), "\n"), 3)
+			},
+		},
+		{
+			"json output",
+			[]string{"password", "--output", "json", "16", "3"},
+
+			func(t *testing.T, output string, err error) {
+				require.NoError(t, err)
+				var results []result
+				require.NoError(t, json.Unmarshal([]byte(output), &results))
+				require.Len(t, results, 3)
+				for _, r := range results {
+					require.Equal(t, "password", r.Kind)
+					require.Len(t, r.Value, 16)
+					require.EqualValues(t, 16, r.Length)
+					require.EqualValues(t, len(passgen.AlphabetDefault), r.AlphabetSize)
+					require.Greater(t, r.Entropy, 0.0)
+				}
+			},
+		},
+		{
+			"jsonl output",
+			[]string{"passphrase", "-o", "jsonl", "4", "3"},
+
+			func(t *testing.T, output string, err error) {
+				require.NoError(t, err)
+				lines := strings.Split(strings.TrimSpace(output), "\n")
+				require.Len(t, lines, 3)
+				for _, line := range lines {
+					var r result
+					require.NoError(t, json.Unmarshal([]byte(line), &r))
+					require.Equal(t, "passphrase", r.Kind)
+					require.Len(t, strings.Split(r.Value, " "), 4)
+					require.EqualValues(t, 4, r.WordCount)
+					require.EqualValues(t, len(passgen.WordListDefault), r.WordListSize)
+				}
+			},
+		},
+		{
+			"csv output",
+			[]string{"passphrase", "--output", "csv", "--separator", ",", "4", "3"},
+
+			func(t *testing.T, output string, err error) {
+				require.NoError(t, err)
+				records, err := csv.NewReader(strings.NewReader(output)).ReadAll()
+				require.NoError(t, err)
+				require.Len(t, records, 4)
+				require.Equal(t, "kind", records[0][0])
+				for _, record := range records[1:] {
+					require.Equal(t, "passphrase", record[0])
+					require.Len(t, strings.Split(record[1], ","), 4)
+				}
+			},
+		},
+		{
+			"nul output",
+			[]string{"passphrase", "--output", "nul", "4", "3"},
+
+			func(t *testing.T, output string, err error) {
+				require.NoError(t, err)
+				require.True(t, strings.HasSuffix(output, "\x00"))
+				passphrases := strings.Split(strings.TrimSuffix(output, "\x00"), "\x00")
+				require.Len(t, passphrases, 3)
+				for _, passphrase := range passphrases {
+					require.Len(t, strings.Split(passphrase, " "), 4)
+				}
+			},
+		},
+		{
+			"invalid output format",
+			[]string{"password", "--output", "xml"},
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name,
+			func(t *testing.T) {
+				rootCmd := buildRootCmd()
+				var outputBuffer strings.Builder
+				rootCmd.SetOut(&outputBuffer)
+				rootCmd.SetErr(new(strings.Builder))
+
+				rootCmd.SetArgs(test.args)
+
+				err := rootCmd.Execute()
+				test.requirements(t, outputBuffer.String(), err)
+			},
+		)
+	}
+}
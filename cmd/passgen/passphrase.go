@@ -4,13 +4,15 @@ import (
 	"bufio"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
-	"unicode/utf8"
+	"unicode"
 
 	"github.com/decentral1se/passgen"
 	"github.com/spf13/cobra"
+	"golang.org/x/text/language"
 )
 
 // buildPassphraseCmd constructs the passphrase subcommand responsible for generating passphrases.
@@ -20,19 +22,39 @@ func buildPassphraseCmd() *cobra.Command {
 	passphraseConfig := struct {
 		count     uint                     // Number of passphrases to generate.
 		wordCount uint                     // Length, in words, of passphrases to generate.
-		separator rune                     // Passphrase word separator.
+		separator string                   // Passphrase word separator.
 		casing    passgen.PassphraseCasing // Passphrase word casing.
 		wordList  []string                 // List of words to pull passphrase words from.
 
-		separatorString string // Intermediate storage for separator flag before translation to rune.
+		randomSeparators string // Characters each separator is drawn from at random, if not empty.
 
 		// At most one of the following values is allowed to be set.
-		casingLower bool // Generate lowercase passphrases.
-		casingUpper bool // Generate uppercase passphrases.
-		casingTitle bool // Generate title-case passphrases.
-		casingNone  bool // Generate passphrases without applying any casing transformation.
+		casingLower       bool // Generate lowercase passphrases.
+		casingUpper       bool // Generate uppercase passphrases.
+		casingTitle       bool // Generate title-case passphrases.
+		casingNone        bool // Generate passphrases without applying any casing transformation.
+		casingRandom      bool // Generate passphrases with each word cased at random.
+		casingCamel       bool // Generate camel-case passphrases.
+		casingAlternating bool // Generate passphrases alternating between lowercase and uppercase words.
+		casingOneCapital  bool // Generate lowercase passphrases with one word, chosen at random, in title case.
 
-		wordListFilename string // Filename of a newline-delimited word list to use in passphrases.
+		languageTag string // BCP 47 tag of the language whose case mappings are used, if not empty.
+
+		wordListFilename string // Filename of a word list file to use in passphrases.
+		wordListName     string // Name of a built-in word list to use in passphrases.
+
+		minWordLength uint // Fewest characters allowed in each passphrase word, or zero for no limit.
+		maxWordLength uint // Most characters allowed in each passphrase word, or zero for no limit.
+		maxLength     uint // Most characters allowed in each passphrase, or zero for no limit.
+
+		digits  uint // Number of digits to inject into each passphrase.
+		symbols uint // Number of symbols to inject into each passphrase.
+
+		showEntropy bool    // Report the entropy of generated passphrases.
+		minEntropy  float64 // Entropy target used to choose the passphrase word count.
+
+		dice  bool     // Build the passphrase from physical dice rolls.
+		rolls []string // Dice rolls, each five digits from 1 to 6, used to choose words.
 	}{
 		passgen.PassphraseCountDefault,
 		passgen.PassphraseWordCountDefault,
@@ -46,8 +68,28 @@ func buildPassphraseCmd() *cobra.Command {
 		false,
 		false,
 		false,
+		false,
+		false,
+		false,
+		false,
+
+		"",
 
 		"",
+		"",
+
+		0,
+		0,
+		0,
+
+		0,
+		0,
+
+		false,
+		0,
+
+		false,
+		nil,
 	}
 
 	// Construct the command.
@@ -111,39 +153,60 @@ func buildPassphraseCmd() *cobra.Command {
 
 		// Define what the passphrase subcommand does when invoked.
 		RunE: func(cmd *cobra.Command, args []string) (err error) {
-			// Attempt to convert the provided separator string (if it exists) to a single rune.
-			if passphraseConfig.separatorString != "" {
-				if utf8.RuneCountInString(passphraseConfig.separatorString) > 1 {
-					return errors.New("separator must be a single character")
-				}
-				passphraseConfig.separator = []rune(passphraseConfig.separatorString)[0]
+			// Ensure the separator is either fixed or drawn at random.
+			if cmd.Flags().Changed("separator") && passphraseConfig.randomSeparators != "" {
+				return errors.New("at most one of --separator and --random-separators is allowed")
+			}
+
+			// Ensure the word count is either provided or chosen from an entropy target.
+			if len(args) > 0 && cmd.Flags().Changed("min-entropy") {
+				return errors.New("at most one of the word count argument and --min-entropy is allowed")
 			}
 
 			// Set the casing based on user flags, ensuring no more than one flag is set.
 			casingSet := false
-			if passphraseConfig.casingLower {
-				passphraseConfig.casing = passgen.PassphraseCasingLower
-				casingSet = true
-			}
-			if passphraseConfig.casingUpper {
+			for _, casingFlag := range []struct {
+				set    bool
+				casing passgen.PassphraseCasing
+			}{
+				{passphraseConfig.casingLower, passgen.PassphraseCasingLower},
+				{passphraseConfig.casingUpper, passgen.PassphraseCasingUpper},
+				{passphraseConfig.casingTitle, passgen.PassphraseCasingTitle},
+				{passphraseConfig.casingNone, passgen.PassphraseCasingNone},
+				{passphraseConfig.casingRandom, passgen.PassphraseCasingRandomWord},
+				{passphraseConfig.casingCamel, passgen.PassphraseCasingCamelCase},
+				{passphraseConfig.casingAlternating, passgen.PassphraseCasingAlternating},
+				{passphraseConfig.casingOneCapital, passgen.PassphraseCasingOneCapital},
+			} {
+				if !casingFlag.set {
+					continue
+				}
 				if casingSet {
 					return errors.New("at most one casing method is allowed")
 				}
-				passphraseConfig.casing = passgen.PassphraseCasingUpper
+				passphraseConfig.casing = casingFlag.casing
 				casingSet = true
 			}
-			if passphraseConfig.casingTitle {
-				if casingSet {
-					return errors.New("at most one casing method is allowed")
+
+			// Parse the language whose case mappings are used if one was provided.
+			var tag language.Tag
+			if passphraseConfig.languageTag != "" {
+				tag, err = language.Parse(passphraseConfig.languageTag)
+				if err != nil {
+					return fmt.Errorf("invalid language provided: %w", err)
 				}
-				passphraseConfig.casing = passgen.PassphraseCasingTitle
-				casingSet = true
 			}
-			if passphraseConfig.casingNone {
-				if casingSet {
-					return errors.New("at most one casing method is allowed")
+
+			// Look up the built-in word list if one was named.
+			if passphraseConfig.wordListName != "" {
+				if passphraseConfig.wordListFilename != "" {
+					return errors.New("at most one of --list and --word-list is allowed")
+				}
+
+				passphraseConfig.wordList, err = passgen.LookupWordList(passphraseConfig.wordListName)
+				if err != nil {
+					return err
 				}
-				passphraseConfig.casing = passgen.PassphraseCasingNone
 			}
 
 			// Attempt to open and read the word list file if provided.
@@ -158,45 +221,111 @@ func buildPassphraseCmd() *cobra.Command {
 					_ = wordListFile.Close()
 				}()
 
-				// Step through each line and append it to the word list.
-				var wordList []string
-				scanner := bufio.NewScanner(wordListFile)
-				for scanner.Scan() {
-					wordList = append(wordList, strings.TrimSpace(scanner.Text()))
+				// Parse the word list, detecting whether it is plain, dice-numbered, or tab-separated.
+				passphraseConfig.wordList, err = passgen.LoadWordList(wordListFile)
+				if err != nil {
+					return fmt.Errorf("%s: %w", passphraseConfig.wordListFilename, err)
 				}
+			}
+
+			// Passphrases built from dice rolls do not use the random source.
+			if passphraseConfig.dice {
+				return runDice(cmd, passgen.PassphraseOptions{
+					Count:     passphraseConfig.count,
+					WordCount: passphraseConfig.wordCount,
+					Separator: passphraseConfig.separator,
+					Casing:    passphraseConfig.casing,
+					WordList:  passphraseConfig.wordList,
+					Language:  tag,
+
+					MinWordLength: passphraseConfig.minWordLength,
+					MaxWordLength: passphraseConfig.maxWordLength,
+				}, passphraseConfig.rolls, len(args) > 0, passphraseConfig.showEntropy)
+			}
 
-				passphraseConfig.wordList = wordList
+			// Dice rolls are only used when building a passphrase from dice.
+			if cmd.Flags().Changed("rolls") {
+				return errors.New("--rolls requires --dice")
+			}
+
+			// Build the generation options based on the command invocation.
+			passphraseOptions := passgen.PassphraseOptions{
+				Count:     passphraseConfig.count,
+				WordCount: passphraseConfig.wordCount,
+				Separator: passphraseConfig.separator,
+				Casing:    passphraseConfig.casing,
+				WordList:  passphraseConfig.wordList,
+				Language:  tag,
+
+				RandomSeparators: passphraseConfig.randomSeparators,
+
+				MinWordLength: passphraseConfig.minWordLength,
+				MaxWordLength: passphraseConfig.maxWordLength,
+				MaxLength:     passphraseConfig.maxLength,
+
+				Digits:  passphraseConfig.digits,
+				Symbols: passphraseConfig.symbols,
+
+				MinEntropy: passphraseConfig.minEntropy,
+			}
+
+			// Choose the word count if an entropy target was provided.
+			passphraseOptions, err = passphraseOptions.Resolve()
+			if err != nil {
+				return err
 			}
 
 			// Generate passphrases based on the command invocation.
-			passphrases, err := passgen.GeneratePassphrases(
-				passphraseConfig.count,
-				passphraseConfig.wordCount,
-				passphraseConfig.separator,
-				passphraseConfig.casing,
-				passphraseConfig.wordList,
-			)
+			passphrases, err := passgen.GeneratePassphrasesWithOptions(passphraseOptions)
+			if err != nil {
+				return err
+			}
+
+			// Determine the entropy of the generated passphrases.
+			entropy, err := passphraseOptions.Entropy()
 			if err != nil {
 				return err
 			}
 
-			// Print out a single passphrase per line.
+			// Report the entropy of the generated passphrases on stderr if requested.
+			if passphraseConfig.showEntropy {
+				fmt.Fprintf(cmd.ErrOrStderr(), "entropy: %.2f bits\n", entropy)
+			}
+
+			// Write out the passphrases along with metadata describing them.
+			results := make([]result, 0, len(passphrases))
 			for _, passphrase := range passphrases {
-				fmt.Fprintln(cmd.OutOrStdout(), passphrase)
+				results = append(results, result{
+					Kind:         "passphrase",
+					Value:        passphrase,
+					WordCount:    passphraseOptions.WordCount,
+					WordListSize: passphraseOptions.WordListSize(),
+					Entropy:      entropy,
+				})
 			}
 
-			return
+			return writeResults(cmd, results)
 		},
 	}
 
 	// Define the flag for the word separator.
 	passphraseCmd.Flags().StringVarP(
-		&passphraseConfig.separatorString,
+		&passphraseConfig.separator,
 		"separator",
 		"s",
+		passgen.PassphraseSeparatorDefault,
+		"passphrase word separator, which may be empty or several characters",
+	)
+
+	// Define the flag for drawing each separator at random, from a default set of characters when
+	// none are given.
+	passphraseCmd.Flags().StringVar(
+		&passphraseConfig.randomSeparators,
+		"random-separators",
 		"",
-		"passphrase word separator",
+		"draw each separator at random from the given characters (default digits and symbols)",
 	)
+	passphraseCmd.Flags().Lookup("random-separators").NoOptDefVal = passgen.PassphraseRandomSeparatorsDefault
 
 	// Define the flag for generating lowercase passphrases.
 	passphraseCmd.Flags().BoolVarP(
@@ -234,14 +363,215 @@ func buildPassphraseCmd() *cobra.Command {
 		"generate passphrases without applying any case transformation",
 	)
 
+	// Define the flags for generating passphrases whose casing differs from word to word.
+	passphraseCmd.Flags().BoolVar(
+		&passphraseConfig.casingRandom,
+		"random-case",
+		false,
+		"generate passphrases with each word lowercase, uppercase, or title case at random",
+	)
+	passphraseCmd.Flags().BoolVar(
+		&passphraseConfig.casingCamel,
+		"camel-case",
+		false,
+		"generate camel-case passphrases",
+	)
+	passphraseCmd.Flags().BoolVar(
+		&passphraseConfig.casingAlternating,
+		"alternating-case",
+		false,
+		"generate passphrases alternating between lowercase and uppercase words",
+	)
+	passphraseCmd.Flags().BoolVar(
+		&passphraseConfig.casingOneCapital,
+		"one-capital",
+		false,
+		"generate lowercase passphrases with one word, chosen at random, in title case",
+	)
+
+	// Define the flag for the language whose case mappings are used.
+	passphraseCmd.Flags().StringVar(
+		&passphraseConfig.languageTag,
+		"language",
+		"",
+		"BCP 47 language tag whose casing rules are used, such as tr or nl (default language-neutral)",
+	)
+
 	// Define the flag for a word list filename.
 	passphraseCmd.Flags().StringVarP(
 		&passphraseConfig.wordListFilename,
 		"word-list",
 		"w",
 		"",
-		"file containing a newline-delimited word list for use in passphrases",
+		"file containing a word list file for use in passphrases",
+	)
+
+	// Define the flag for a built-in word list name.
+	passphraseCmd.Flags().StringVar(
+		&passphraseConfig.wordListName,
+		"list",
+		"",
+		"name of a built-in word list for use in passphrases (see the lists subcommand)",
+	)
+
+	// Define the flags for the lengths of words allowed in passphrases.
+	passphraseCmd.Flags().UintVar(
+		&passphraseConfig.minWordLength,
+		"min-word-length",
+		0,
+		"fewest characters allowed in each passphrase word (0 for no limit)",
+	)
+	passphraseCmd.Flags().UintVar(
+		&passphraseConfig.maxWordLength,
+		"max-word-length",
+		0,
+		"most characters allowed in each passphrase word (0 for no limit)",
+	)
+
+	// Define the flag for the length of generated passphrases.
+	passphraseCmd.Flags().UintVar(
+		&passphraseConfig.maxLength,
+		"max-length",
+		0,
+		"most characters allowed in each passphrase, separators included (0 for no limit)",
+	)
+
+	// Define the flags for injecting digits and symbols into passphrases.
+	passphraseCmd.Flags().UintVar(
+		&passphraseConfig.digits,
+		"digits",
+		0,
+		"number of random digits to insert at random positions in each passphrase",
+	)
+	passphraseCmd.Flags().UintVar(
+		&passphraseConfig.symbols,
+		"symbols",
+		0,
+		"number of random symbols to insert at random positions in each passphrase",
+	)
+
+	// Define the flag for choosing the passphrase word count from an entropy target.
+	passphraseCmd.Flags().Float64Var(
+		&passphraseConfig.minEntropy,
+		"min-entropy",
+		0,
+		"minimum bits of entropy per passphrase, used to choose the word count (cannot be combined with a word count)",
+	)
+
+	// Define the flag for reporting the entropy of generated passphrases.
+	passphraseCmd.Flags().BoolVar(
+		&passphraseConfig.showEntropy,
+		"show-entropy",
+		false,
+		"print the entropy of generated passphrases to stderr",
+	)
+
+	// Define the flag for building a passphrase from physical dice rolls.
+	passphraseCmd.Flags().BoolVar(
+		&passphraseConfig.dice,
+		"dice",
+		false,
+		"build a passphrase from physical dice rolls, read from --rolls or stdin",
+	)
+
+	// Define the flag for providing dice rolls.
+	passphraseCmd.Flags().StringSliceVar(
+		&passphraseConfig.rolls,
+		"rolls",
+		nil,
+		"comma-separated dice rolls for --dice, each five digits from 1 to 6, e.g. 16655,43215",
 	)
 
 	return passphraseCmd
 }
+
+// runDice builds a passphrase from physical dice rolls for the passphrase subcommand. Rolls are
+// taken from the rolls flag when provided. Otherwise one roll per word is prompted for when stdin
+// is a terminal, or every roll is read from stdin separated by whitespace or commas.
+func runDice(
+	cmd *cobra.Command, // The invoked passphrase subcommand.
+	opts passgen.PassphraseOptions, // Options for the separator, casing, and word list.
+	rolls []string, // Dice rolls provided by the rolls flag.
+	wordCountSet bool, // Whether the word count was provided as an argument.
+	showEntropy bool, // Report the entropy of the passphrase.
+) error {
+	// Dice rolls make a single passphrase whose length is chosen by the user.
+	if opts.Count != 1 {
+		return errors.New("--dice builds a single passphrase")
+	}
+	if cmd.Flags().Changed("min-entropy") {
+		return errors.New("--min-entropy cannot be used with --dice")
+	}
+	if cmd.Flags().Changed("max-length") {
+		return errors.New("--max-length cannot be used with --dice")
+	}
+	if cmd.Flags().Changed("digits") || cmd.Flags().Changed("symbols") {
+		return errors.New("--digits and --symbols cannot be used with --dice")
+	}
+	if cmd.Flags().Changed("random-separators") {
+		return errors.New("--random-separators cannot be used with --dice")
+	}
+
+	in := cmd.InOrStdin()
+
+	switch {
+	case len(rolls) > 0:
+		// Use the rolls provided by the flag.
+
+	case isTerminal(in):
+		// Prompt for the roll of each word, asking again after an invalid roll.
+		scanner := bufio.NewScanner(in)
+		for len(rolls) < int(opts.WordCount) {
+			fmt.Fprintf(cmd.ErrOrStderr(), "roll %d of %d: ", len(rolls)+1, opts.WordCount)
+			if !scanner.Scan() {
+				if err := scanner.Err(); err != nil {
+					return err
+				}
+				return errors.New("too few dice rolls provided")
+			}
+
+			roll := strings.TrimSpace(scanner.Text())
+			if _, err := passgen.DiceIndex(roll); err != nil {
+				fmt.Fprintln(cmd.ErrOrStderr(), err)
+				continue
+			}
+			rolls = append(rolls, roll)
+		}
+
+	default:
+		// Read every roll from stdin.
+		input, err := io.ReadAll(in)
+		if err != nil {
+			return err
+		}
+		rolls = strings.FieldsFunc(string(input), func(r rune) bool {
+			return r == ',' || unicode.IsSpace(r)
+		})
+	}
+
+	// Ensure the rolls match the word count if one was provided.
+	if wordCountSet && uint(len(rolls)) != opts.WordCount {
+		return fmt.Errorf("expected %d dice rolls, got %d", opts.WordCount, len(rolls))
+	}
+
+	// Build the passphrase from the rolls.
+	passphrase, err := passgen.DicePassphrase(rolls, opts)
+	if err != nil {
+		return err
+	}
+
+	// Report the entropy of the passphrase on stderr if requested.
+	entropy := passgen.DiceEntropy(uint(len(rolls)))
+	if showEntropy {
+		fmt.Fprintf(cmd.ErrOrStderr(), "entropy: %.2f bits\n", entropy)
+	}
+
+	// Write out the passphrase along with metadata describing it.
+	return writeResults(cmd, []result{{
+		Kind:         "passphrase",
+		Value:        passphrase,
+		WordCount:    uint(len(rolls)),
+		WordListSize: passgen.DiceWordListLength,
+		Entropy:      entropy,
+	}})
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"io/ioutil"
 	"strconv"
 	"strings"
@@ -41,6 +42,31 @@ func TestPassphraseCommand(t *testing.T) {
 		require.NoError(t, err)
 	}
 
+	// Write a word list with a malformed line to a temp file.
+	malformedWordListFile, err := ioutil.TempFile("", "")
+
+	defer func() {
+		_ = malformedWordListFile.Close()
+	}()
+
+	require.NoError(t, err)
+	malformedWordListFilename := malformedWordListFile.Name()
+	_, err = malformedWordListFile.WriteString("alfa\nbravo charlie\n")
+	require.NoError(t, err)
+
+	// Write a Turkish word list, whose dotted and dotless i are cased differently in Turkish, to a
+	// temp file.
+	turkishWordListFile, err := ioutil.TempFile("", "")
+
+	defer func() {
+		_ = turkishWordListFile.Close()
+	}()
+
+	require.NoError(t, err)
+	turkishWordListFilename := turkishWordListFile.Name()
+	_, err = turkishWordListFile.WriteString("istanbul\nizmir\nırmak\n")
+	require.NoError(t, err)
+
 	var tests = []testDef{
 		{
 			"rational defaults",
@@ -52,7 +78,7 @@ func TestPassphraseCommand(t *testing.T) {
 				passphrases := strings.Split(strings.TrimSpace(output), "\n")
 				require.Len(t, passphrases, passgen.PassphraseCountDefault)
 				for _, passphrase := range passphrases {
-					words := strings.Split(passphrase, string(passgen.PassphraseSeparatorDefault))
+					words := strings.Split(passphrase, passgen.PassphraseSeparatorDefault)
 					require.Len(t, words, passgen.PassphraseWordCountDefault)
 					for _, word := range words {
 						require.Contains(t, passgen.WordListDefault, word)
@@ -198,12 +224,98 @@ func TestPassphraseCommand(t *testing.T) {
 			},
 		},
 		{
-			"invalid separator",
+			"multi-character separator",
 			nil,
 			map[string]string{
 				"separator": "==",
 			},
 
+			func(t *testing.T, output string, err error) {
+				require.NoError(t, err)
+				passphrases := strings.Split(strings.TrimSpace(output), "\n")
+				require.Len(t, passphrases, passgen.PassphraseCountDefault)
+				for _, passphrase := range passphrases {
+					words := strings.Split(passphrase, "==")
+					require.Len(t, words, passgen.PassphraseWordCountDefault)
+					for _, word := range words {
+						require.Contains(t, passgen.WordListDefault, word)
+					}
+				}
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"empty separator",
+			[]string{"--separator", "", "--lowercase"},
+			nil,
+
+			func(t *testing.T, output string, err error) {
+				require.NoError(t, err)
+				passphrases := strings.Split(strings.TrimSpace(output), "\n")
+				require.Len(t, passphrases, passgen.PassphraseCountDefault)
+				for _, passphrase := range passphrases {
+					require.NotContains(t, passphrase, " ")
+				}
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"random separators",
+			nil,
+			map[string]string{
+				"random-separators": "0123",
+			},
+
+			func(t *testing.T, output string, err error) {
+				require.NoError(t, err)
+				passphrases := strings.Split(strings.TrimSpace(output), "\n")
+				require.Len(t, passphrases, passgen.PassphraseCountDefault)
+				for _, passphrase := range passphrases {
+					words := strings.FieldsFunc(passphrase, func(char rune) bool {
+						return strings.ContainsRune("0123", char)
+					})
+					require.Len(t, words, passgen.PassphraseWordCountDefault)
+					for _, word := range words {
+						require.Contains(t, passgen.WordListDefault, word)
+					}
+				}
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"default random separators",
+			[]string{"--random-separators", "--show-entropy"},
+			nil,
+
+			func(t *testing.T, output string, err error) {
+				require.NoError(t, err)
+				passphrases := strings.Split(strings.TrimSpace(output), "\n")
+				require.Len(t, passphrases, passgen.PassphraseCountDefault)
+				for _, passphrase := range passphrases {
+					words := strings.FieldsFunc(passphrase, func(char rune) bool {
+						return strings.ContainsRune(passgen.PassphraseRandomSeparatorsDefault, char)
+					})
+					require.Len(t, words, passgen.PassphraseWordCountDefault)
+				}
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"separator and random separators",
+			nil,
+			map[string]string{
+				"separator":         "-",
+				"random-separators": "0123",
+			},
+
 			func(t *testing.T, output string, err error) {
 				require.Error(t, err)
 			},
@@ -215,7 +327,7 @@ func TestPassphraseCommand(t *testing.T) {
 			"custom separator",
 			nil,
 			map[string]string{
-				"separator": string(passgen.PassphraseSeparatorDash),
+				"separator": passgen.PassphraseSeparatorDash,
 			},
 
 			func(t *testing.T, output string, err error) {
@@ -223,7 +335,7 @@ func TestPassphraseCommand(t *testing.T) {
 				passphrases := strings.Split(strings.TrimSpace(output), "\n")
 				require.Len(t, passphrases, passgen.PassphraseCountDefault)
 				for _, passphrase := range passphrases {
-					words := strings.Split(passphrase, string(passgen.PassphraseSeparatorDash))
+					words := strings.Split(passphrase, passgen.PassphraseSeparatorDash)
 					require.Len(t, words, passgen.PassphraseWordCountDefault)
 					for _, word := range words {
 						require.Contains(t, passgen.WordListDefault, word)
@@ -291,7 +403,7 @@ func TestPassphraseCommand(t *testing.T) {
 				passphrases := strings.Split(strings.TrimSpace(output), "\n")
 				require.Len(t, passphrases, passgen.PassphraseCountDefault)
 				for _, passphrase := range passphrases {
-					words := strings.Split(passphrase, string(passgen.PassphraseSeparatorDefault))
+					words := strings.Split(passphrase, passgen.PassphraseSeparatorDefault)
 					require.Len(t, words, passgen.PassphraseWordCountDefault)
 					for _, word := range words {
 						require.Contains(t, passgen.WordListDefault, strings.ToLower(word))
@@ -315,7 +427,7 @@ func TestPassphraseCommand(t *testing.T) {
 				passphrases := strings.Split(strings.TrimSpace(output), "\n")
 				require.Len(t, passphrases, passgen.PassphraseCountDefault)
 				for _, passphrase := range passphrases {
-					words := strings.Split(passphrase, string(passgen.PassphraseSeparatorDefault))
+					words := strings.Split(passphrase, passgen.PassphraseSeparatorDefault)
 					require.Len(t, words, passgen.PassphraseWordCountDefault)
 					for _, word := range words {
 						require.Contains(t, passgen.WordListDefault, strings.ToLower(word))
@@ -339,7 +451,7 @@ func TestPassphraseCommand(t *testing.T) {
 				passphrases := strings.Split(strings.TrimSpace(output), "\n")
 				require.Len(t, passphrases, passgen.PassphraseCountDefault)
 				for _, passphrase := range passphrases {
-					words := strings.Split(passphrase, string(passgen.PassphraseSeparatorDefault))
+					words := strings.Split(passphrase, passgen.PassphraseSeparatorDefault)
 					require.Len(t, words, passgen.PassphraseWordCountDefault)
 					for _, word := range words {
 						require.Contains(t, passgen.WordListDefault, strings.ToLower(word))
@@ -363,7 +475,7 @@ func TestPassphraseCommand(t *testing.T) {
 				passphrases := strings.Split(strings.TrimSpace(output), "\n")
 				require.Len(t, passphrases, passgen.PassphraseCountDefault)
 				for _, passphrase := range passphrases {
-					words := strings.Split(passphrase, string(passgen.PassphraseSeparatorDefault))
+					words := strings.Split(passphrase, passgen.PassphraseSeparatorDefault)
 					require.Len(t, words, passgen.PassphraseWordCountDefault)
 					for _, word := range words {
 						require.Contains(t, passgen.WordListDefault, word)
@@ -374,6 +486,166 @@ func TestPassphraseCommand(t *testing.T) {
 			nil,
 			nil,
 		},
+		{
+			"random-case flag",
+			nil,
+			map[string]string{
+				"random-case": "true",
+			},
+
+			func(t *testing.T, output string, err error) {
+				require.NoError(t, err)
+				passphrases := strings.Split(strings.TrimSpace(output), "\n")
+				require.Len(t, passphrases, passgen.PassphraseCountDefault)
+				for _, passphrase := range passphrases {
+					words := strings.Split(passphrase, passgen.PassphraseSeparatorDefault)
+					require.Len(t, words, passgen.PassphraseWordCountDefault)
+					for _, word := range words {
+						require.Contains(t, passgen.WordListDefault, strings.ToLower(word))
+						require.Contains(t, []string{strings.ToLower(word), strings.ToUpper(word), strings.Title(strings.ToLower(word))}, word)
+					}
+				}
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"camel-case flag",
+			nil,
+			map[string]string{
+				"camel-case": "true",
+				"separator":  passgen.PassphraseSeparatorDot,
+			},
+
+			func(t *testing.T, output string, err error) {
+				require.NoError(t, err)
+				passphrases := strings.Split(strings.TrimSpace(output), "\n")
+				require.Len(t, passphrases, passgen.PassphraseCountDefault)
+				for _, passphrase := range passphrases {
+					words := strings.Split(passphrase, passgen.PassphraseSeparatorDot)
+					require.Len(t, words, passgen.PassphraseWordCountDefault)
+					require.Equal(t, strings.ToLower(words[0]), words[0])
+					for _, word := range words[1:] {
+						require.Contains(t, passgen.WordListDefault, strings.ToLower(word))
+						require.Equal(t, strings.Title(strings.ToLower(word)), word)
+					}
+				}
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"alternating-case flag",
+			nil,
+			map[string]string{
+				"alternating-case": "true",
+			},
+
+			func(t *testing.T, output string, err error) {
+				require.NoError(t, err)
+				passphrases := strings.Split(strings.TrimSpace(output), "\n")
+				require.Len(t, passphrases, passgen.PassphraseCountDefault)
+				for _, passphrase := range passphrases {
+					words := strings.Split(passphrase, passgen.PassphraseSeparatorDefault)
+					require.Len(t, words, passgen.PassphraseWordCountDefault)
+					for i, word := range words {
+						require.Contains(t, passgen.WordListDefault, strings.ToLower(word))
+						if i%2 == 0 {
+							require.Equal(t, strings.ToLower(word), word)
+						} else {
+							require.Equal(t, strings.ToUpper(word), word)
+						}
+					}
+				}
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"one-capital flag",
+			nil,
+			map[string]string{
+				"one-capital": "true",
+			},
+
+			func(t *testing.T, output string, err error) {
+				require.NoError(t, err)
+				passphrases := strings.Split(strings.TrimSpace(output), "\n")
+				require.Len(t, passphrases, passgen.PassphraseCountDefault)
+				for _, passphrase := range passphrases {
+					words := strings.Split(passphrase, passgen.PassphraseSeparatorDefault)
+					require.Len(t, words, passgen.PassphraseWordCountDefault)
+					capitals := 0
+					for _, word := range words {
+						require.Contains(t, passgen.WordListDefault, strings.ToLower(word))
+						if word != strings.ToLower(word) {
+							capitals++
+						}
+					}
+					require.Equal(t, 1, capitals)
+				}
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"too many casing flags",
+			nil,
+			map[string]string{
+				"camel-case":  "true",
+				"one-capital": "true",
+			},
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"language flag",
+			nil,
+			map[string]string{
+				"uppercase": "true",
+				"language":  "tr",
+				"word-list": turkishWordListFilename,
+			},
+
+			func(t *testing.T, output string, err error) {
+				require.NoError(t, err)
+				passphrases := strings.Split(strings.TrimSpace(output), "\n")
+				require.Len(t, passphrases, passgen.PassphraseCountDefault)
+				for _, passphrase := range passphrases {
+					words := strings.Split(passphrase, passgen.PassphraseSeparatorDefault)
+					require.Len(t, words, passgen.PassphraseWordCountDefault)
+					for _, word := range words {
+						require.Contains(t, []string{"İSTANBUL", "İZMİR", "IRMAK"}, word)
+					}
+				}
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"invalid language",
+			nil,
+			map[string]string{
+				"language": "not a language",
+			},
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+
+			nil,
+			nil,
+		},
 		{
 			"nonexistent word list file",
 			nil,
@@ -400,7 +672,7 @@ func TestPassphraseCommand(t *testing.T) {
 				passphrases := strings.Split(strings.TrimSpace(output), "\n")
 				require.Len(t, passphrases, passgen.PassphraseCountDefault)
 				for _, passphrase := range passphrases {
-					words := strings.Split(passphrase, string(passgen.PassphraseSeparatorDefault))
+					words := strings.Split(passphrase, passgen.PassphraseSeparatorDefault)
 					require.Len(t, words, passgen.PassphraseWordCountDefault)
 					for _, word := range words {
 						require.Contains(t, alternateWordList, word)
@@ -411,34 +683,510 @@ func TestPassphraseCommand(t *testing.T) {
 			nil,
 			nil,
 		},
-	}
+		{
+			"malformed word list file",
+			nil,
+			map[string]string{
+				"word-list": malformedWordListFilename,
+			},
 
-	for _, test := range tests {
-		t.Run(
-			test.name,
-			func(t *testing.T) {
-				var setupContext interface{}
-				if test.setup != nil {
-					setupContext = test.setup()
-				}
+			func(t *testing.T, output string, err error) {
+				var wordListErr *passgen.WordListError
+				require.True(t, errors.As(err, &wordListErr))
+				require.EqualValues(t, 2, wordListErr.Line)
+			},
 
-				passphraseCmd := buildPassphraseCmd()
-				var outputBuffer strings.Builder
-				passphraseCmd.SetOut(&outputBuffer)
+			nil,
+			nil,
+		},
+		{
+			"show entropy",
+			nil,
+			map[string]string{
+				"show-entropy": "true",
+			},
 
-				passphraseCmd.SetArgs(test.args)
-				for flag, value := range test.flags {
-					err := passphraseCmd.Flags().Set(flag, value)
-					require.NoError(t, err)
-				}
+			func(t *testing.T, output string, err error) {
+				require.NoError(t, err)
+				require.Len(t, strings.Split(strings.TrimSpace(output), "\n"), 1)
+			},
 
-				err := passphraseCmd.Execute()
-				test.requirements(t, outputBuffer.String(), err)
+			nil,
+			nil,
+		},
+		{
+			"minimum entropy",
+			nil,
+			map[string]string{
+				"min-entropy": "128",
+			},
 
-				if test.teardown != nil {
-					test.teardown(setupContext)
+			func(t *testing.T, output string, err error) {
+				require.NoError(t, err)
+				passphrases := strings.Split(strings.TrimSpace(output), "\n")
+				require.Len(t, passphrases, passgen.PassphraseCountDefault)
+				for _, passphrase := range passphrases {
+					words := strings.Split(passphrase, passgen.PassphraseSeparatorDefault)
+					require.Len(t, words, 10)
 				}
 			},
+
+			nil,
+			nil,
+		},
+		{
+			"minimum entropy with word count",
+			[]string{"6"},
+			map[string]string{
+				"min-entropy": "128",
+			},
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"word length limits",
+			[]string{"6", "8"},
+			map[string]string{
+				"min-word-length": "3",
+				"max-word-length": "6",
+			},
+
+			func(t *testing.T, output string, err error) {
+				require.NoError(t, err)
+				passphrases := strings.Split(strings.TrimSpace(output), "\n")
+				require.Len(t, passphrases, 8)
+				for _, passphrase := range passphrases {
+					words := strings.Split(passphrase, passgen.PassphraseSeparatorDefault)
+					require.Len(t, words, 6)
+					for _, word := range words {
+						require.Contains(t, passgen.WordListDefault, word)
+						require.GreaterOrEqual(t, len(word), 3)
+						require.LessOrEqual(t, len(word), 6)
+					}
+				}
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"minimum word length above maximum",
+			nil,
+			map[string]string{
+				"min-word-length": "7",
+				"max-word-length": "6",
+			},
+
+			func(t *testing.T, output string, err error) {
+				require.True(t, errors.Is(err, passgen.ErrInvalidWordLength))
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"maximum length",
+			[]string{"6", "8"},
+			map[string]string{
+				"max-length": "32",
+			},
+
+			func(t *testing.T, output string, err error) {
+				require.NoError(t, err)
+				passphrases := strings.Split(strings.TrimSpace(output), "\n")
+				require.Len(t, passphrases, 8)
+				for _, passphrase := range passphrases {
+					require.LessOrEqual(t, len(passphrase), 32)
+					require.Len(t, strings.Split(passphrase, passgen.PassphraseSeparatorDefault), 6)
+				}
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"unsatisfiable maximum length",
+			nil,
+			map[string]string{
+				"max-length": "10",
+			},
+
+			func(t *testing.T, output string, err error) {
+				require.True(t, errors.Is(err, passgen.ErrRequirementsUnsatisfied))
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"digits and symbols",
+			[]string{"6", "8"},
+			map[string]string{
+				"digits":  "2",
+				"symbols": "1",
+			},
+
+			func(t *testing.T, output string, err error) {
+				require.NoError(t, err)
+				passphrases := strings.Split(strings.TrimSpace(output), "\n")
+				require.Len(t, passphrases, 8)
+				for _, passphrase := range passphrases {
+					var digits, symbols int
+					for _, char := range passphrase {
+						switch {
+						case strings.ContainsRune(passgen.AlphabetNumeric, char):
+							digits++
+						case strings.ContainsRune(passgen.AlphabetSpecial, char):
+							symbols++
+						}
+					}
+					require.Equal(t, 2, digits)
+					require.Equal(t, 1, symbols)
+					require.Len(t, strings.Split(passphrase, passgen.PassphraseSeparatorDefault), 6)
+				}
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"too many digits",
+			nil,
+			map[string]string{
+				"digits": strconv.Itoa(passgen.PassphraseDigitsMax + 1),
+			},
+
+			func(t *testing.T, output string, err error) {
+				var rangeErr *passgen.RangeError
+				require.True(t, errors.As(err, &rangeErr))
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"unreachable minimum entropy",
+			nil,
+			map[string]string{
+				"min-entropy": "100000",
+			},
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"built-in word list",
+			nil,
+			map[string]string{
+				"list": "bip39-english",
+			},
+
+			func(t *testing.T, output string, err error) {
+				require.NoError(t, err)
+				words := strings.Split(strings.TrimSpace(output), passgen.PassphraseSeparatorDefault)
+				require.Len(t, words, passgen.PassphraseWordCountDefault)
+				for _, word := range words {
+					require.Contains(t, passgen.WordListBIP39English, word)
+				}
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"unknown built-in word list",
+			nil,
+			map[string]string{
+				"list": "eff-tiny",
+			},
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"built-in word list and word list file",
+			nil,
+			map[string]string{
+				"list":      "reinhold",
+				"word-list": "words.txt",
+			},
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+
+			nil,
+			nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name,
+			func(t *testing.T) {
+				var setupContext interface{}
+				if test.setup != nil {
+					setupContext = test.setup()
+				}
+
+				passphraseCmd := buildPassphraseCmd()
+				var outputBuffer strings.Builder
+				passphraseCmd.SetOut(&outputBuffer)
+
+				passphraseCmd.SetArgs(test.args)
+				for flag, value := range test.flags {
+					err := passphraseCmd.Flags().Set(flag, value)
+					require.NoError(t, err)
+				}
+
+				err := passphraseCmd.Execute()
+				test.requirements(t, outputBuffer.String(), err)
+
+				if test.teardown != nil {
+					test.teardown(setupContext)
+				}
+			},
+		)
+	}
+}
+
+func TestPassphraseDiceCommand(t *testing.T) {
+	type testReqs func(t *testing.T, output string, err error)
+
+	type testDef struct {
+		name  string
+		args  []string
+		flags map[string]string
+		input string
+
+		requirements testReqs
+	}
+
+	var tests = []testDef{
+		{
+			"rolls flag",
+			nil,
+			map[string]string{
+				"dice":      "true",
+				"rolls":     "16655,43215,25364",
+				"separator": "-",
+			},
+			"",
+
+			func(t *testing.T, output string, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "contusion-overhang-enjoyer\n", output)
+			},
+		},
+		{
+			"rolls on stdin",
+			nil,
+			map[string]string{
+				"dice":         "true",
+				"show-entropy": "true",
+			},
+			"16655 43215,25364\n11111\n",
+
+			func(t *testing.T, output string, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "contusion overhang enjoyer abacus\n", output)
+			},
+		},
+		{
+			"rolls matching word count",
+			[]string{"3"},
+			map[string]string{
+				"dice":  "true",
+				"rolls": "16655,43215,25364",
+			},
+			"",
+
+			func(t *testing.T, output string, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "contusion overhang enjoyer\n", output)
+			},
+		},
+		{
+			"rolls not matching word count",
+			[]string{"4"},
+			map[string]string{
+				"dice":  "true",
+				"rolls": "16655,43215,25364",
+			},
+			"",
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+		},
+		{
+			"invalid roll",
+			nil,
+			map[string]string{
+				"dice":  "true",
+				"rolls": "16655,43215,25370",
+			},
+			"",
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+		},
+		{
+			"too few rolls",
+			nil,
+			map[string]string{
+				"dice": "true",
+			},
+			"16655\n",
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+		},
+		{
+			"multiple passphrases",
+			[]string{"3", "2"},
+			map[string]string{
+				"dice":  "true",
+				"rolls": "16655,43215,25364",
+			},
+			"",
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+		},
+		{
+			"entropy target",
+			nil,
+			map[string]string{
+				"dice":        "true",
+				"rolls":       "16655,43215,25364",
+				"min-entropy": "64",
+			},
+			"",
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+		},
+		{
+			"maximum length",
+			nil,
+			map[string]string{
+				"dice":       "true",
+				"rolls":      "16655,43215,25364",
+				"max-length": "32",
+			},
+			"",
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+		},
+		{
+			"digits",
+			nil,
+			map[string]string{
+				"dice":   "true",
+				"rolls":  "16655,43215,25364",
+				"digits": "1",
+			},
+			"",
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+		},
+		{
+			"random casing",
+			nil,
+			map[string]string{
+				"dice":        "true",
+				"rolls":       "16655,43215,25364",
+				"random-case": "true",
+			},
+			"",
+
+			func(t *testing.T, output string, err error) {
+				require.True(t, errors.Is(err, passgen.ErrDiceCasing))
+			},
+		},
+		{
+			"one capital casing",
+			nil,
+			map[string]string{
+				"dice":        "true",
+				"rolls":       "16655,43215,25364",
+				"one-capital": "true",
+			},
+			"",
+
+			func(t *testing.T, output string, err error) {
+				require.True(t, errors.Is(err, passgen.ErrDiceCasing))
+			},
+		},
+		{
+			"random separators",
+			nil,
+			map[string]string{
+				"dice":              "true",
+				"rolls":             "16655,43215,25364",
+				"random-separators": "0123",
+			},
+			"",
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+		},
+		{
+			"rolls without dice",
+			nil,
+			map[string]string{
+				"rolls": "16655,43215,25364",
+			},
+			"",
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name,
+			func(t *testing.T) {
+				passphraseCmd := buildPassphraseCmd()
+				var outputBuffer strings.Builder
+				passphraseCmd.SetOut(&outputBuffer)
+				passphraseCmd.SetErr(new(strings.Builder))
+				passphraseCmd.SetIn(strings.NewReader(test.input))
+
+				passphraseCmd.SetArgs(test.args)
+				for flag, value := range test.flags {
+					err := passphraseCmd.Flags().Set(flag, value)
+					require.NoError(t, err)
+				}
+
+				err := passphraseCmd.Execute()
+				test.requirements(t, outputBuffer.String(), err)
+			},
 		)
 	}
 }
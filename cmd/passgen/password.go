@@ -4,7 +4,6 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
-	"strings"
 
 	"github.com/decentral1se/passgen"
 	"github.com/spf13/cobra"
@@ -15,24 +14,24 @@ func buildPasswordCmd() *cobra.Command {
 	// Build a configuration struct for converting commandline input into parameters for a passgen
 	// GeneratePasswords function call.
 	passwordConfig := struct {
-		count    uint   // Number of passwords to generate.
-		length   uint   // Length of passwords to generate.
-		alphabet string // Alphabet to use when generating passwords.
-
-		allowUppercase bool // Allow uppercase characters in passwords.
-		allowLowercase bool // Allow lowercase characters in passwords.
-		allowNumeric   bool // Allow numeric characters in passwords.
-		allowSpecial   bool // Allow special characters in passwords.
-		allowAmbiguous bool // Allow ambiguous characters in passwords.
+		count  uint // Number of passwords to generate.
+		length uint // Length of passwords to generate.
+
+		alphabet alphabetConfig // Alphabet and class requirements of generated passwords.
+
+		showEntropy bool    // Report the entropy of generated passwords.
+		minEntropy  float64 // Entropy target used to choose the password length.
+
+		pronounceable bool // Generate pronounceable passwords of alternating consonants and vowels.
 	}{
 		passgen.PasswordCountDefault,
 		passgen.PasswordLengthDefault,
-		passgen.AlphabetDefault,
+
+		alphabetConfig{},
 
 		false,
-		false,
-		false,
-		false,
+		0,
+
 		false,
 	}
 
@@ -97,153 +96,147 @@ func buildPasswordCmd() *cobra.Command {
 
 		// Define what the password subcommand does when invoked.
 		RunE: func(cmd *cobra.Command, args []string) (err error) {
-			// Determine the alphabet to use.
-			if passwordConfig.alphabet == "" {
-				// Instantiate a string builder for efficient alphabet construction.
-				var b strings.Builder
-
-				// Determine if the user wants passwords which include lowercase characters.
-				if passwordConfig.allowLowercase {
-					// Determine whether or not ambiguous characters should be allowed in the output.
-					if passwordConfig.allowAmbiguous {
-						_, err = b.WriteString(passgen.AlphabetLowerAmbiguous)
-						if err != nil {
-							return err
-						}
-					} else {
-						_, err = b.WriteString(passgen.AlphabetLower)
-						if err != nil {
-							return err
-						}
-					}
-				}
+			// Ensure the length is either provided or chosen from an entropy target.
+			if len(args) > 0 && cmd.Flags().Changed("min-entropy") {
+				return errors.New("at most one of the length argument and --min-entropy is allowed")
+			}
 
-				// Determine if the user wants passwords which include uppercase characters.
-				if passwordConfig.allowUppercase {
-					// Determine whether or not ambiguous characters should be allowed in the output.
-					if passwordConfig.allowAmbiguous {
-						_, err = b.WriteString(passgen.AlphabetUpperAmbiguous)
-						if err != nil {
-							return err
-						}
-					} else {
-						_, err = b.WriteString(passgen.AlphabetUpper)
-						if err != nil {
-							return err
-						}
-					}
-				}
+			// Pronounceable passwords are generated separately, as they do not use an alphabet.
+			if passwordConfig.pronounceable {
+				return runPronounceable(cmd, passgen.PronounceableOptions{
+					Count:      passwordConfig.count,
+					Length:     passwordConfig.length,
+					MinEntropy: passwordConfig.minEntropy,
+				}, passwordConfig.showEntropy)
+			}
 
-				// Determine if the user wants passwords which include numeric characters.
-				if passwordConfig.allowNumeric {
-					// Determine whether or not ambiguous characters should be allowed in the output.
-					if passwordConfig.allowAmbiguous {
-						_, err = b.WriteString(passgen.AlphabetNumericAmbiguous)
-						if err != nil {
-							return err
-						}
-					} else {
-						_, err = b.WriteString(passgen.AlphabetNumeric)
-						if err != nil {
-							return err
-						}
-					}
-				}
+			// Determine the alphabet and class requirements based on user input.
+			alphabet, requirements := passwordConfig.alphabet.build()
 
-				// Determine if the user wants passwords which include numeric characters.
-				if passwordConfig.allowSpecial {
-					_, err := b.WriteString(passgen.AlphabetSpecial)
-					if err != nil {
-						return err
-					}
-				}
+			// Build the generation options based on the command invocation.
+			passwordOptions := passgen.PasswordOptions{
+				Count:        passwordConfig.count,
+				Length:       passwordConfig.length,
+				Alphabet:     alphabet,
+				Requirements: requirements,
+				MinEntropy:   passwordConfig.minEntropy,
+			}
 
-				// Build the alphabet based on user input.
-				passwordConfig.alphabet = b.String()
-
-				// If the user did not specify allowance of any of lowercase, uppercase, numeric, or
-				// special characters, rely on the default alphabet.
-				if passwordConfig.alphabet == "" {
-					// Determine whether or not ambiguous characters should be allowed in the output.
-					if passwordConfig.allowAmbiguous {
-						passwordConfig.alphabet = passgen.AlphabetDefaultAmbiguous
-					} else {
-						passwordConfig.alphabet = passgen.AlphabetDefault
-					}
-				}
+			// Choose the password length if an entropy target was provided.
+			passwordOptions, err = passwordOptions.Resolve()
+			if err != nil {
+				return err
 			}
 
 			// Generate passwords based on the command invocation.
-			passwords, err := passgen.GeneratePasswords(
-				passwordConfig.count,
-				passwordConfig.length,
-				passwordConfig.alphabet,
-			)
+			passwords, err := passgen.GeneratePasswordsWithOptions(passwordOptions)
+			if err != nil {
+				return err
+			}
+
+			// Determine the entropy of the generated passwords.
+			entropy, err := passwordOptions.Entropy()
 			if err != nil {
 				return err
 			}
 
-			// Print out a single password per line.
+			// Report the entropy of the generated passwords on stderr if requested.
+			if passwordConfig.showEntropy {
+				fmt.Fprintf(cmd.ErrOrStderr(), "entropy: %.2f bits\n", entropy)
+			}
+
+			// Write out the passwords along with metadata describing them.
+			results := make([]result, 0, len(passwords))
 			for _, password := range passwords {
-				fmt.Fprintln(cmd.OutOrStdout(), password)
+				results = append(results, result{
+					Kind:         "password",
+					Value:        password,
+					Length:       passwordOptions.Length,
+					AlphabetSize: passwordOptions.AlphabetSize(),
+					Entropy:      entropy,
+				})
 			}
 
-			return
+			return writeResults(cmd, results)
 		},
 	}
 
-	// Define the flag for allowance of lowercase characters in generated passwords.
-	passwordCmd.Flags().BoolVarP(
-		&passwordConfig.allowLowercase,
-		"lowercase",
-		"l",
-		false,
-		"allow lowercase letters in passwords",
-	)
+	// Define the flags for the alphabet and class requirements of generated passwords.
+	passwordConfig.alphabet.addFlags(passwordCmd)
 
-	// Define the flag for allowance of uppercase characters in generated passwords.
-	passwordCmd.Flags().BoolVarP(
-		&passwordConfig.allowUppercase,
-		"uppercase",
-		"u",
-		false,
-		"allow uppercase letters in passwords",
+	// Define the flag for choosing the password length from an entropy target.
+	passwordCmd.Flags().Float64Var(
+		&passwordConfig.minEntropy,
+		"min-entropy",
+		0,
+		"minimum bits of entropy per password, used to choose the length (cannot be combined with a length)",
 	)
 
-	// Define the flag for allowance of numeric characters in generated passwords.
-	passwordCmd.Flags().BoolVarP(
-		&passwordConfig.allowNumeric,
-		"numeric",
-		"n",
+	// Define the flag for generating pronounceable passwords.
+	passwordCmd.Flags().BoolVar(
+		&passwordConfig.pronounceable,
+		"pronounceable",
 		false,
-		"allow numeric characters in passwords",
+		"generate pronounceable passwords of alternating consonants and vowels",
 	)
 
-	// Define the flag for allowance of special characters in generated passwords.
-	passwordCmd.Flags().BoolVarP(
-		&passwordConfig.allowSpecial,
-		"special",
-		"s",
+	// Define the flag for reporting the entropy of generated passwords.
+	passwordCmd.Flags().BoolVar(
+		&passwordConfig.showEntropy,
+		"show-entropy",
 		false,
-		"allow special characters in passwords",
+		"print the entropy of generated passwords to stderr",
 	)
 
-	// Define the flag for allowance of ambiguous characters in generated passwords.
-	passwordCmd.Flags().BoolVarP(
-		&passwordConfig.allowAmbiguous,
-		"ambiguous",
-		"a",
-		false,
-		"allow ambiguous characters in passwords",
-	)
+	return passwordCmd
+}
 
-	// Define the flag for specification of a custom alphabet used by generated passwords.
-	passwordCmd.Flags().StringVar(
-		&passwordConfig.alphabet,
-		"alphabet",
-		"",
-		"alphabet to use for password generation (supersedes other flags)",
-	)
+// runPronounceable generates pronounceable passwords for the password subcommand.
+func runPronounceable(
+	cmd *cobra.Command, // The invoked password subcommand.
+	opts passgen.PronounceableOptions, // Options for generating pronounceable passwords.
+	showEntropy bool, // Report the entropy of generated passwords.
+) error {
+	// Pronounceable passwords draw from fixed alphabets, so alphabet flags cannot be honored.
+	for _, flag := range alphabetFlags {
+		if cmd.Flags().Changed(flag) {
+			return fmt.Errorf("--%s cannot be used with --pronounceable", flag)
+		}
+	}
 
-	return passwordCmd
+	// Choose the password length if an entropy target was provided.
+	opts, err := opts.Resolve()
+	if err != nil {
+		return err
+	}
+
+	// Generate pronounceable passwords based on the command invocation.
+	passwords, err := passgen.GeneratePronounceable(opts)
+	if err != nil {
+		return err
+	}
+
+	// Determine the entropy of the generated passwords.
+	entropy, err := opts.Entropy()
+	if err != nil {
+		return err
+	}
+
+	// Report the entropy of the generated passwords on stderr if requested.
+	if showEntropy {
+		fmt.Fprintf(cmd.ErrOrStderr(), "entropy: %.2f bits\n", entropy)
+	}
+
+	// Write out the passwords along with metadata describing them.
+	results := make([]result, 0, len(passwords))
+	for _, password := range passwords {
+		results = append(results, result{
+			Kind:    "pronounceable",
+			Value:   password,
+			Length:  opts.Length,
+			Entropy: entropy,
+		})
+	}
+
+	return writeResults(cmd, results)
 }
@@ -297,6 +297,191 @@ func TestPasswordCommand(t *testing.T) {
 				}
 			},
 
+			nil,
+			nil,
+		},
+		{
+			"minimum special characters",
+			nil,
+			map[string]string{
+				"min-special": "3",
+			},
+
+			func(t *testing.T, output string, err error) {
+				require.NoError(t, err)
+				passwords := strings.Split(strings.TrimSpace(output), "\n")
+				require.Len(t, passwords, passgen.PasswordCountDefault)
+				for _, password := range passwords {
+					require.Equal(t, passgen.PasswordLengthDefault, utf8.RuneCountInString(password))
+					var specials int
+					for _, char := range password {
+						require.Contains(t, passgen.AlphabetDefault+passgen.AlphabetSpecial, string(char))
+						if strings.ContainsRune(passgen.AlphabetSpecial, char) {
+							specials++
+						}
+					}
+					require.GreaterOrEqual(t, specials, 3)
+				}
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"every character class required",
+			[]string{"5", "64"},
+			map[string]string{
+				"require-lowercase": "true",
+				"require-uppercase": "true",
+				"require-numeric":   "true",
+				"require-special":   "true",
+			},
+
+			func(t *testing.T, output string, err error) {
+				require.NoError(t, err)
+				passwords := strings.Split(strings.TrimSpace(output), "\n")
+				require.Len(t, passwords, 64)
+				for _, password := range passwords {
+					require.True(t, strings.ContainsAny(password, passgen.AlphabetLower))
+					require.True(t, strings.ContainsAny(password, passgen.AlphabetUpper))
+					require.True(t, strings.ContainsAny(password, passgen.AlphabetNumeric))
+					require.True(t, strings.ContainsAny(password, passgen.AlphabetSpecial))
+				}
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"required characters exceed length",
+			[]string{"5"},
+			map[string]string{
+				"min-numeric": "3",
+				"min-special": "3",
+			},
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"required class missing from custom alphabet",
+			nil,
+			map[string]string{
+				"alphabet":        "abc",
+				"require-numeric": "true",
+			},
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"show entropy",
+			nil,
+			map[string]string{
+				"show-entropy": "true",
+			},
+
+			func(t *testing.T, output string, err error) {
+				require.NoError(t, err)
+				require.Len(t, strings.Split(strings.TrimSpace(output), "\n"), 1)
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"minimum entropy",
+			nil,
+			map[string]string{
+				"min-entropy": "128",
+			},
+
+			func(t *testing.T, output string, err error) {
+				require.NoError(t, err)
+				passwords := strings.Split(strings.TrimSpace(output), "\n")
+				require.Len(t, passwords, passgen.PasswordCountDefault)
+				for _, password := range passwords {
+					require.Equal(t, 22, utf8.RuneCountInString(password))
+				}
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"minimum entropy with length",
+			[]string{"12"},
+			map[string]string{
+				"min-entropy": "128",
+			},
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"unreachable minimum entropy",
+			nil,
+			map[string]string{
+				"min-entropy": "100000",
+			},
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"pronounceable passwords",
+			[]string{"12", "8"},
+			map[string]string{
+				"pronounceable": "true",
+			},
+
+			func(t *testing.T, output string, err error) {
+				require.NoError(t, err)
+				passwords := strings.Split(strings.TrimSpace(output), "\n")
+				require.Len(t, passwords, 8)
+				for _, password := range passwords {
+					require.Equal(t, 12, utf8.RuneCountInString(password))
+					for i, char := range password {
+						if i%2 == 0 {
+							require.Contains(t, passgen.AlphabetConsonant, string(char))
+						} else {
+							require.Contains(t, passgen.AlphabetVowel, string(char))
+						}
+					}
+				}
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"pronounceable passwords with alphabet flags",
+			nil,
+			map[string]string{
+				"pronounceable": "true",
+				"special":       "true",
+			},
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+
 			nil,
 			nil,
 		},
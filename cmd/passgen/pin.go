@@ -0,0 +1,183 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/decentral1se/passgen"
+	"github.com/spf13/cobra"
+)
+
+// buildPINCmd constructs the pin subcommand responsible for generating numeric PINs and codes.
+func buildPINCmd() *cobra.Command {
+	// Build a configuration struct for converting commandline input into parameters for a passgen
+	// GeneratePINs function call.
+	pinConfig := struct {
+		count  uint // Number of PINs to generate.
+		length uint // Length, in digits, of PINs to generate.
+
+		rejectRepeated   bool // Reject PINs consisting of a repeated digit or block of digits.
+		rejectSequential bool // Reject PINs consisting of an ascending or descending run.
+		rejectCommon     bool // Reject commonly chosen PINs.
+		rejectWeak       bool // Reject PINs matching any weak pattern.
+
+		showEntropy bool // Report the entropy of generated PINs.
+	}{
+		passgen.PINCountDefault,
+		passgen.PINLengthDefault,
+
+		false,
+		false,
+		false,
+		false,
+
+		false,
+	}
+
+	// Construct the command.
+	pinCmd := &cobra.Command{
+		Use:   "pin [length] [count]",
+		Short: "Generate numeric PINs and codes",
+
+		Aliases: []string{
+			"code",
+		},
+
+		Args: func(cmd *cobra.Command, args []string) error {
+			// Don't allow more than two positional arguments (length and count.)
+			if len(args) > 2 {
+				return errors.New("too many args provided")
+			}
+
+			// The first argument is the PIN length.
+			if len(args) > 0 {
+				length, err := strconv.ParseUint(
+					args[0],
+					10,
+					64,
+				)
+				if err != nil {
+					return errors.New("invalid length provided")
+				}
+
+				// Bounds check the length for the platform.
+				if length > uint64(uintMax) {
+					return errors.New("invalid length provided")
+				}
+
+				// Update the configuration with the parsed information.
+				pinConfig.length = uint(length)
+			}
+
+			// The second argument is the PIN count.
+			if len(args) > 1 {
+				count, err := strconv.ParseUint(
+					args[1],
+					10,
+					64,
+				)
+				if err != nil {
+					return errors.New("invalid count provided")
+				}
+
+				// Bounds check the count for the platform.
+				if count > uint64(uintMax) {
+					return errors.New("invalid count provided")
+				}
+
+				// Update the configuration with the parsed information.
+				pinConfig.count = uint(count)
+			}
+
+			return nil
+		},
+
+		// Define what the pin subcommand does when invoked.
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			// Build the generation options based on the command invocation.
+			pinOptions := passgen.PINOptions{
+				Count:  pinConfig.count,
+				Length: pinConfig.length,
+
+				RejectRepeated:   pinConfig.rejectRepeated || pinConfig.rejectWeak,
+				RejectSequential: pinConfig.rejectSequential || pinConfig.rejectWeak,
+				RejectCommon:     pinConfig.rejectCommon || pinConfig.rejectWeak,
+			}
+
+			// Generate PINs based on the command invocation.
+			pins, err := passgen.GeneratePINs(pinOptions)
+			if err != nil {
+				return err
+			}
+
+			// Determine the entropy of the generated PINs.
+			entropy, err := pinOptions.Entropy()
+			if err != nil {
+				return err
+			}
+
+			// Report the entropy of the generated PINs on stderr if requested.
+			if pinConfig.showEntropy {
+				fmt.Fprintf(cmd.ErrOrStderr(), "entropy: %.2f bits\n", entropy)
+			}
+
+			// Write out the PINs along with metadata describing them.
+			results := make([]result, 0, len(pins))
+			for _, pin := range pins {
+				results = append(results, result{
+					Kind:         "pin",
+					Value:        pin,
+					Length:       pinOptions.Length,
+					AlphabetSize: uint(len(passgen.AlphabetNumericAmbiguous)),
+					Entropy:      entropy,
+				})
+			}
+
+			return writeResults(cmd, results)
+		},
+	}
+
+	// Define the flag for rejecting repeated digits.
+	pinCmd.Flags().BoolVar(
+		&pinConfig.rejectRepeated,
+		"reject-repeated",
+		false,
+		"reject PINs made of a repeated digit or block of digits, e.g. 1111 or 1212",
+	)
+
+	// Define the flag for rejecting ascending and descending runs.
+	pinCmd.Flags().BoolVar(
+		&pinConfig.rejectSequential,
+		"reject-sequential",
+		false,
+		"reject PINs made of an ascending or descending run, e.g. 1234 or 9876",
+	)
+
+	// Define the flag for rejecting common PINs.
+	pinCmd.Flags().BoolVar(
+		&pinConfig.rejectCommon,
+		"reject-common",
+		false,
+		"reject commonly chosen PINs, e.g. 2580 or 1004",
+	)
+
+	// Define the flag for rejecting every weak pattern.
+	pinCmd.Flags().BoolVarP(
+		&pinConfig.rejectWeak,
+		"reject-weak",
+		"w",
+		false,
+		"reject repeated, sequential, and common PINs",
+	)
+
+	// Define the flag for reporting the entropy of generated PINs.
+	pinCmd.Flags().BoolVar(
+		&pinConfig.showEntropy,
+		"show-entropy",
+		false,
+		"print the entropy of generated PINs to stderr",
+	)
+
+	return pinCmd
+}
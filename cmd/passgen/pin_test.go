@@ -0,0 +1,224 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/decentral1se/passgen"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPINCommand(t *testing.T) {
+	type testReqs func(t *testing.T, output string, err error)
+
+	type testDef struct {
+		name  string
+		args  []string
+		flags map[string]string
+
+		requirements testReqs
+		setup        func() interface{}
+		teardown     func(interface{})
+	}
+
+	var tests = []testDef{
+		{
+			"rational defaults",
+			nil,
+			nil,
+
+			func(t *testing.T, output string, err error) {
+				require.NoError(t, err)
+				pins := strings.Split(strings.TrimSpace(output), "\n")
+				require.Len(t, pins, passgen.PINCountDefault)
+				for _, pin := range pins {
+					require.Len(t, pin, passgen.PINLengthDefault)
+					for _, digit := range pin {
+						require.Contains(t, passgen.AlphabetNumericAmbiguous, string(digit))
+					}
+				}
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"too many arguments",
+			[]string{"1", "2", "3"},
+			nil,
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"mistyped length argument",
+			[]string{"x"},
+			nil,
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"length argument too small",
+			[]string{strconv.FormatUint(passgen.PINLengthMin-1, 10)},
+			nil,
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"length argument too large for platform",
+			[]string{"16"},
+			nil,
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+
+			func() interface{} {
+				originalUintMax := uintMax
+				uintMax = 15
+				return originalUintMax
+			},
+
+			func(setupContext interface{}) {
+				uintMax = setupContext.(uint)
+			},
+		},
+		{
+			"mistyped count argument",
+			[]string{
+				strconv.FormatUint(passgen.PINLengthDefault, 10),
+				"x",
+			},
+			nil,
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"count argument too large",
+			[]string{
+				strconv.FormatUint(passgen.PINLengthDefault, 10),
+				strconv.FormatUint(passgen.PINCountMax+1, 10),
+			},
+			nil,
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"count argument too large for platform",
+			[]string{
+				"4",
+				"16",
+			},
+			nil,
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+
+			func() interface{} {
+				originalUintMax := uintMax
+				uintMax = 15
+				return originalUintMax
+			},
+
+			func(setupContext interface{}) {
+				uintMax = setupContext.(uint)
+			},
+		},
+		{
+			"weak PINs rejected",
+			[]string{"4", "256"},
+			map[string]string{
+				"reject-weak": "true",
+			},
+
+			func(t *testing.T, output string, err error) {
+				require.NoError(t, err)
+				pins := strings.Split(strings.TrimSpace(output), "\n")
+				require.Len(t, pins, 256)
+				for _, pin := range pins {
+					require.Len(t, pin, 4)
+					require.NotEqual(t, strings.Repeat(pin[:1], 4), pin)
+					require.NotEqual(t, "1234", pin)
+					require.NotEqual(t, "2580", pin)
+				}
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"show entropy",
+			nil,
+			map[string]string{
+				"reject-repeated":   "true",
+				"reject-sequential": "true",
+				"reject-common":     "true",
+				"show-entropy":      "true",
+			},
+
+			func(t *testing.T, output string, err error) {
+				require.NoError(t, err)
+				require.Len(t, strings.Split(strings.TrimSpace(output), "\n"), 1)
+			},
+
+			nil,
+			nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name,
+			func(t *testing.T) {
+				var setupContext interface{}
+				if test.setup != nil {
+					setupContext = test.setup()
+				}
+
+				pinCmd := buildPINCmd()
+				var outputBuffer strings.Builder
+				pinCmd.SetOut(&outputBuffer)
+				pinCmd.SetErr(new(strings.Builder))
+
+				pinCmd.SetArgs(test.args)
+				for flag, value := range test.flags {
+					err := pinCmd.Flags().Set(flag, value)
+					require.NoError(t, err)
+				}
+
+				err := pinCmd.Execute()
+				test.requirements(t, outputBuffer.String(), err)
+
+				if test.teardown != nil {
+					test.teardown(setupContext)
+				}
+			},
+		)
+	}
+}
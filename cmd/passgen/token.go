@@ -0,0 +1,137 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/decentral1se/passgen"
+	"github.com/spf13/cobra"
+)
+
+// buildTokenCmd constructs the token subcommand responsible for generating prefixed API tokens.
+func buildTokenCmd() *cobra.Command {
+	// Build a configuration struct for converting commandline input into parameters for a passgen
+	// GenerateTokens function call.
+	tokenConfig := struct {
+		count  uint   // Number of tokens to generate.
+		prefix string // Prefix identifying the kind of token.
+		bytes  uint   // Number of random bytes in each token body.
+
+		showEntropy bool // Report the entropy of generated tokens.
+	}{
+		passgen.TokenCountDefault,
+		passgen.TokenPrefixDefault,
+		passgen.TokenBytesDefault,
+
+		false,
+	}
+
+	// Construct the command.
+	tokenCmd := &cobra.Command{
+		Use:   "token [count]",
+		Short: "Generate prefixed API tokens with checksums",
+
+		Aliases: []string{
+			"tok",
+		},
+
+		Args: func(cmd *cobra.Command, args []string) error {
+			// Don't allow more than one positional argument (count.)
+			if len(args) > 1 {
+				return errors.New("too many args provided")
+			}
+
+			// The first argument is the token count.
+			if len(args) > 0 {
+				count, err := strconv.ParseUint(
+					args[0],
+					10,
+					64,
+				)
+				if err != nil {
+					return errors.New("invalid count provided")
+				}
+
+				// Bounds check the count for the platform.
+				if count > uint64(uintMax) {
+					return errors.New("invalid count provided")
+				}
+
+				// Update the configuration with the parsed information.
+				tokenConfig.count = uint(count)
+			}
+
+			return nil
+		},
+
+		// Define what the token subcommand does when invoked.
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			// Build the generation options based on the command invocation.
+			tokenOptions := passgen.TokenOptions{
+				Count:  tokenConfig.count,
+				Prefix: tokenConfig.prefix,
+				Bytes:  tokenConfig.bytes,
+			}
+
+			// Generate tokens based on the command invocation.
+			tokens, err := passgen.GenerateTokens(tokenOptions)
+			if err != nil {
+				return err
+			}
+
+			// Determine the entropy of the generated tokens.
+			entropy, err := tokenOptions.Entropy()
+			if err != nil {
+				return err
+			}
+
+			// Report the entropy of the generated tokens on stderr if requested.
+			if tokenConfig.showEntropy {
+				fmt.Fprintf(cmd.ErrOrStderr(), "entropy: %.2f bits\n", entropy)
+			}
+
+			// Write out the tokens along with metadata describing them.
+			results := make([]result, 0, len(tokens))
+			for _, token := range tokens {
+				results = append(results, result{
+					Kind:         "token",
+					Value:        token,
+					Length:       tokenOptions.Length(),
+					AlphabetSize: 62,
+					Entropy:      entropy,
+				})
+			}
+
+			return writeResults(cmd, results)
+		},
+	}
+
+	// Define the flag for the token prefix.
+	tokenCmd.Flags().StringVarP(
+		&tokenConfig.prefix,
+		"prefix",
+		"p",
+		passgen.TokenPrefixDefault,
+		"prefix identifying the kind of token, made of letters and digits",
+	)
+
+	// Define the flag for the number of random bytes in each token.
+	tokenCmd.Flags().UintVarP(
+		&tokenConfig.bytes,
+		"bytes",
+		"b",
+		passgen.TokenBytesDefault,
+		"number of random bytes in each token body",
+	)
+
+	// Define the flag for reporting the entropy of generated tokens.
+	tokenCmd.Flags().BoolVar(
+		&tokenConfig.showEntropy,
+		"show-entropy",
+		false,
+		"print the entropy of generated tokens to stderr",
+	)
+
+	return tokenCmd
+}
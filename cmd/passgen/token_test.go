@@ -0,0 +1,179 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/decentral1se/passgen"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenCommand(t *testing.T) {
+	type testReqs func(t *testing.T, output string, err error)
+
+	type testDef struct {
+		name  string
+		args  []string
+		flags map[string]string
+
+		requirements testReqs
+		setup        func() interface{}
+		teardown     func(interface{})
+	}
+
+	var tests = []testDef{
+		{
+			"rational defaults",
+			nil,
+			nil,
+
+			func(t *testing.T, output string, err error) {
+				require.NoError(t, err)
+				tokens := strings.Split(strings.TrimSpace(output), "\n")
+				require.Len(t, tokens, passgen.TokenCountDefault)
+				for _, token := range tokens {
+					require.NoError(t, passgen.ValidateToken(token, passgen.TokenPrefixDefault))
+				}
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"too many arguments",
+			[]string{"1", "2"},
+			nil,
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"mistyped count argument",
+			[]string{"x"},
+			nil,
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"count argument too large",
+			[]string{strconv.FormatUint(passgen.TokenCountMax+1, 10)},
+			nil,
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"count argument too large for platform",
+			[]string{"16"},
+			nil,
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+
+			func() interface{} {
+				originalUintMax := uintMax
+				uintMax = 15
+				return originalUintMax
+			},
+
+			func(setupContext interface{}) {
+				uintMax = setupContext.(uint)
+			},
+		},
+		{
+			"custom prefix and bytes",
+			[]string{"4"},
+			map[string]string{
+				"prefix":       "svc",
+				"bytes":        strconv.FormatUint(passgen.TokenBytesMin, 10),
+				"show-entropy": "true",
+			},
+
+			func(t *testing.T, output string, err error) {
+				require.NoError(t, err)
+				tokens := strings.Split(strings.TrimSpace(output), "\n")
+				require.Len(t, tokens, 4)
+				for _, token := range tokens {
+					require.True(t, strings.HasPrefix(token, "svc_"))
+					require.NoError(t, passgen.ValidateToken(token, "svc"))
+				}
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"invalid prefix",
+			nil,
+			map[string]string{
+				"prefix": "svc-key",
+			},
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"bytes too small",
+			nil,
+			map[string]string{
+				"bytes": strconv.FormatUint(passgen.TokenBytesMin-1, 10),
+			},
+
+			func(t *testing.T, output string, err error) {
+				require.Error(t, err)
+			},
+
+			nil,
+			nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name,
+			func(t *testing.T) {
+				var setupContext interface{}
+				if test.setup != nil {
+					setupContext = test.setup()
+				}
+
+				tokenCmd := buildTokenCmd()
+				var outputBuffer strings.Builder
+				tokenCmd.SetOut(&outputBuffer)
+				tokenCmd.SetErr(new(strings.Builder))
+
+				tokenCmd.SetArgs(test.args)
+				for flag, value := range test.flags {
+					err := tokenCmd.Flags().Set(flag, value)
+					require.NoError(t, err)
+				}
+
+				err := tokenCmd.Execute()
+				test.requirements(t, outputBuffer.String(), err)
+
+				if test.teardown != nil {
+					test.teardown(setupContext)
+				}
+			},
+		)
+	}
+}
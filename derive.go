@@ -0,0 +1,189 @@
+package passgen
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// Domain separation label prefixed to the salt of every derived password. Changing it would change
+// every derived password.
+const deriveSaltLabel = "passgen derive v1"
+
+// DeriveOptions configures the deterministic derivation of site passwords from a master secret.
+// The same master secret and options always derive the same password, so passwords never need to
+// be stored, and a single password may be rotated by incrementing the counter.
+type DeriveOptions struct {
+	Site       string // Site the password is used for, such as example.com.
+	Login      string // Login the password is used with, such as a username or email address.
+	Counter    uint   // Counter distinguishing successive passwords for the same site and login.
+	Iterations uint   // PBKDF2 iterations used to stretch the master secret.
+
+	Length       uint               // Length of the derived password.
+	Alphabet     string             // Alphabet to pull password characters from.
+	Requirements []ClassRequirement // Character classes the password must include.
+	Policy       Policy             // Policy the password must comply with.
+}
+
+// DefaultDeriveOptions returns derivation options populated with the package defaults for the
+// given site and login.
+func DefaultDeriveOptions(site string, login string) DeriveOptions {
+	return DeriveOptions{
+		Site:       site,
+		Login:      login,
+		Counter:    DeriveCounterDefault,
+		Iterations: DeriveIterationsDefault,
+
+		Length:   PasswordLengthDefault,
+		Alphabet: AlphabetDefault,
+	}
+}
+
+// Validate checks the options against the package limits. ErrDeriveSiteEmpty is returned for an
+// empty site and a *RangeError for an out of bounds iteration count, along with any error
+// PasswordOptions.Validate returns for the length, alphabet, class requirements, and policy.
+func (o DeriveOptions) Validate() error {
+	// Validate the supplied site.
+	if o.Site == "" {
+		return ErrDeriveSiteEmpty
+	}
+
+	// Validate the supplied iterations parameter.
+	if o.Iterations < DeriveIterationsMin || o.Iterations > DeriveIterationsMax {
+		return &RangeError{"iterations", DeriveIterationsMin, DeriveIterationsMax}
+	}
+
+	return o.passwordOptions().Validate()
+}
+
+// Entropy returns the number of bits of entropy in a password drawn at random with the options.
+// A derived password can never hold more entropy than the master secret it is derived from.
+func (o DeriveOptions) Entropy() (float64, error) {
+	err := o.Validate()
+	if err != nil {
+		return 0, err
+	}
+
+	return o.passwordOptions().Entropy()
+}
+
+// passwordOptions returns the options used to draw a single password from the derived key stream.
+func (o DeriveOptions) passwordOptions() PasswordOptions {
+	return PasswordOptions{
+		Count:        1,
+		Length:       o.Length,
+		Alphabet:     o.Alphabet,
+		Requirements: o.Requirements,
+		Policy:       o.Policy,
+	}
+}
+
+// salt returns the PBKDF2 salt for the options. Each field is length-prefixed, so that distinct
+// sites and logins never produce the same salt.
+func (o DeriveOptions) salt() []byte {
+	var (
+		salt   = []byte(deriveSaltLabel) // Salt built from the label followed by each field.
+		length [4]byte                   // Big-endian length of a field.
+		count  [8]byte                   // Big-endian counter.
+	)
+
+	binary.BigEndian.PutUint32(length[:], uint32(len(o.Site)))
+	salt = append(salt, length[:]...)
+	salt = append(salt, o.Site...)
+	binary.BigEndian.PutUint32(length[:], uint32(len(o.Login)))
+	salt = append(salt, length[:]...)
+	salt = append(salt, o.Login...)
+	binary.BigEndian.PutUint64(count[:], uint64(o.Counter))
+	salt = append(salt, count[:]...)
+
+	return salt
+}
+
+// pbkdf2SHA256 stretches the password into a key of the given length with PBKDF2-HMAC-SHA256, as
+// specified by RFC 8018.
+func pbkdf2SHA256(password []byte, salt []byte, iterations uint, keyLength int) []byte {
+	prf := hmac.New(sha256.New, password)
+
+	var (
+		key   []byte  // Concatenation of the blocks derived so far.
+		index [4]byte // Big-endian index of the current block, beginning at one.
+		u     []byte  // Output of the latest iteration of the pseudorandom function.
+	)
+	for i := uint32(1); len(key) < keyLength; i++ {
+		// The first iteration hashes the salt followed by the block index.
+		binary.BigEndian.PutUint32(index[:], i)
+		prf.Reset()
+		prf.Write(salt)
+		prf.Write(index[:])
+		u = prf.Sum(u[:0])
+		block := append([]byte{}, u...)
+
+		// Each later iteration hashes the output of the one before it, and every output is
+		// combined into the block.
+		for n := uint(1); n < iterations; n++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for j := range block {
+				block[j] ^= u[j]
+			}
+		}
+
+		key = append(key, block...)
+	}
+
+	return key[:keyLength]
+}
+
+// zeroReader is an endless source of zero bytes.
+type zeroReader struct{}
+
+// Read implements the io.Reader interface.
+func (zeroReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 0
+	}
+
+	return len(p), nil
+}
+
+// DerivePassword deterministically derives a password from the master secret and options.
+//
+// The master secret is stretched with PBKDF2-HMAC-SHA256, salted with the site, login, and counter.
+// The resulting key drives an AES-256-CTR key stream, which is used as the random source of a
+// Generator, so the password is drawn exactly as GeneratePasswordsWithOptions would draw it,
+// including the rejection of passwords which do not meet the class requirements or the policy.
+// Changing the policy therefore only changes the passwords which did not comply with it.
+func DerivePassword(master []byte, opts DeriveOptions) (string, error) {
+	// Validate the master secret and options.
+	if len(master) == 0 {
+		return "", ErrDeriveMasterEmpty
+	}
+	err := opts.Validate()
+	if err != nil {
+		return "", err
+	}
+
+	// Stretch the master secret into a key unique to the site, login, and counter.
+	key := pbkdf2SHA256(master, opts.salt(), opts.Iterations, 32)
+
+	// Expand the key into an endless key stream.
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", err
+	}
+	source := cipher.StreamReader{
+		S: cipher.NewCTR(block, make([]byte, aes.BlockSize)),
+		R: zeroReader{},
+	}
+
+	// Draw the password from the key stream.
+	passwords, err := NewGenerator(source).PasswordsWithOptions(opts.passwordOptions())
+	if err != nil {
+		return "", err
+	}
+
+	return passwords[0], nil
+}
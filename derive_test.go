@@ -0,0 +1,264 @@
+package passgen
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDerivePassword(t *testing.T) {
+	// Use the fewest iterations allowed to keep the tests fast.
+	baseOptions := DefaultDeriveOptions("example.com", "user@example.com")
+	baseOptions.Iterations = DeriveIterationsMin
+	master := []byte("correct horse battery staple")
+
+	basePassword, err := DerivePassword(master, baseOptions)
+	require.NoError(t, err)
+	require.Len(t, basePassword, PasswordLengthDefault)
+	for _, char := range basePassword {
+		require.Contains(t, AlphabetDefault, string(char))
+	}
+
+	// Ensure derivation is deterministic and stable across releases.
+	password, err := DerivePassword(master, baseOptions)
+	require.NoError(t, err)
+	require.Equal(t, basePassword, password)
+	require.Equal(t, "rnxKcPNtCsF3fvZv", basePassword)
+
+	type testReqs func(t *testing.T, password string, err error)
+
+	type testDef struct {
+		name    string
+		master  []byte
+		options func(opts DeriveOptions) DeriveOptions
+
+		requirements testReqs
+	}
+
+	var tests = []testDef{
+		{
+			"different master secret",
+			[]byte("correct horse battery stapler"),
+			func(opts DeriveOptions) DeriveOptions { return opts },
+
+			func(t *testing.T, password string, err error) {
+				require.NoError(t, err)
+				require.NotEqual(t, basePassword, password)
+			},
+		},
+		{
+			"different site",
+			master,
+			func(opts DeriveOptions) DeriveOptions {
+				opts.Site = "example.org"
+				return opts
+			},
+
+			func(t *testing.T, password string, err error) {
+				require.NoError(t, err)
+				require.NotEqual(t, basePassword, password)
+			},
+		},
+		{
+			"different login",
+			master,
+			func(opts DeriveOptions) DeriveOptions {
+				opts.Login = "admin@example.com"
+				return opts
+			},
+
+			func(t *testing.T, password string, err error) {
+				require.NoError(t, err)
+				require.NotEqual(t, basePassword, password)
+			},
+		},
+		{
+			"incremented counter",
+			master,
+			func(opts DeriveOptions) DeriveOptions {
+				opts.Counter++
+				return opts
+			},
+
+			func(t *testing.T, password string, err error) {
+				require.NoError(t, err)
+				require.NotEqual(t, basePassword, password)
+			},
+		},
+		{
+			"site and login boundary moved",
+			master,
+			func(opts DeriveOptions) DeriveOptions {
+				opts.Site = "example.comuser@"
+				opts.Login = "example.com"
+				return opts
+			},
+
+			func(t *testing.T, password string, err error) {
+				require.NoError(t, err)
+				require.NotEqual(t, basePassword, password)
+			},
+		},
+		{
+			"class requirements",
+			master,
+			func(opts DeriveOptions) DeriveOptions {
+				opts.Length = PasswordLengthMin
+				opts.Alphabet = AlphabetLower + AlphabetNumeric + AlphabetSpecial
+				opts.Requirements = []ClassRequirement{
+					{Characters: AlphabetNumeric, Min: 2},
+					{Characters: AlphabetSpecial, Min: 2},
+				}
+				return opts
+			},
+
+			func(t *testing.T, password string, err error) {
+				require.NoError(t, err)
+				require.Len(t, password, PasswordLengthMin)
+				var numeric, special int
+				for _, char := range password {
+					if strings.ContainsRune(AlphabetNumeric, char) {
+						numeric++
+					}
+					if strings.ContainsRune(AlphabetSpecial, char) {
+						special++
+					}
+				}
+				require.GreaterOrEqual(t, numeric, 2)
+				require.GreaterOrEqual(t, special, 2)
+			},
+		},
+		{
+			"empty master secret",
+			nil,
+			func(opts DeriveOptions) DeriveOptions { return opts },
+
+			func(t *testing.T, password string, err error) {
+				require.Empty(t, password)
+				require.True(t, errors.Is(err, ErrDeriveMasterEmpty))
+			},
+		},
+		{
+			"empty site",
+			master,
+			func(opts DeriveOptions) DeriveOptions {
+				opts.Site = ""
+				return opts
+			},
+
+			func(t *testing.T, password string, err error) {
+				require.Empty(t, password)
+				require.True(t, errors.Is(err, ErrDeriveSiteEmpty))
+			},
+		},
+		{
+			"too few iterations",
+			master,
+			func(opts DeriveOptions) DeriveOptions {
+				opts.Iterations = DeriveIterationsMin - 1
+				return opts
+			},
+
+			func(t *testing.T, password string, err error) {
+				require.Empty(t, password)
+				var rangeErr *RangeError
+				require.True(t, errors.As(err, &rangeErr))
+			},
+		},
+		{
+			"length too short",
+			master,
+			func(opts DeriveOptions) DeriveOptions {
+				opts.Length = PasswordLengthMin - 1
+				return opts
+			},
+
+			func(t *testing.T, password string, err error) {
+				require.Empty(t, password)
+				var rangeErr *RangeError
+				require.True(t, errors.As(err, &rangeErr))
+			},
+		},
+		{
+			"alphabet too small",
+			master,
+			func(opts DeriveOptions) DeriveOptions {
+				opts.Alphabet = "a"
+				return opts
+			},
+
+			func(t *testing.T, password string, err error) {
+				require.Empty(t, password)
+				require.True(t, errors.Is(err, ErrAlphabetTooSmall))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name,
+			func(t *testing.T) {
+				password, err := DerivePassword(test.master, test.options(baseOptions))
+				test.requirements(t, password, err)
+			},
+		)
+	}
+}
+
+func TestDeriveOptionsEntropy(t *testing.T) {
+	opts := DefaultDeriveOptions("example.com", "")
+	entropy, err := opts.Entropy()
+	require.NoError(t, err)
+
+	expected, err := DefaultPasswordOptions().Entropy()
+	require.NoError(t, err)
+	require.Equal(t, expected, entropy)
+
+	opts.Site = ""
+	_, err = opts.Entropy()
+	require.True(t, errors.Is(err, ErrDeriveSiteEmpty))
+}
+
+func TestPBKDF2SHA256(t *testing.T) {
+	type testDef struct {
+		password   string
+		salt       string
+		iterations uint
+		key        string
+	}
+
+	// Test vectors from RFC 7914, section 11.
+	var tests = []testDef{
+		{
+			"passwd",
+			"salt",
+			1,
+			"55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc" +
+				"49ca9cccf179b645991664b39d77ef317c71b845b1e30bd509112041d3a19783",
+		},
+		{
+			"Password",
+			"NaCl",
+			80000,
+			"4ddcd8f60b98be21830cee5ef22701f9641a4418d04c0414aeff08876b34ab56" +
+				"a1d425a1225833549adb841b51c9b3176a272bdebba1d078478f62b397f33c8d",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.password,
+			func(t *testing.T) {
+				key := pbkdf2SHA256([]byte(test.password), []byte(test.salt), test.iterations, 64)
+				require.Equal(t, test.key, hex.EncodeToString(key))
+
+				// Shorter keys are a prefix of longer ones.
+				key = pbkdf2SHA256([]byte(test.password), []byte(test.salt), test.iterations, 20)
+				require.Equal(t, test.key[:40], hex.EncodeToString(key))
+			},
+		)
+	}
+}
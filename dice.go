@@ -0,0 +1,84 @@
+package passgen
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// DiceIndex returns the word list index chosen by a roll of five dice, written as the digits shown
+// on each die in the order they are read, such as "16655". Rolls are numbered as in the EFF
+// diceware lists, so "11111" chooses the first word and "66666" the last. ErrInvalidDiceRoll is
+// returned for a roll which is not five digits from 1 to 6.
+func DiceIndex(roll string) (uint, error) {
+	if len(roll) != DiceRollsPerWord || !isDiceRoll(roll) {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidDiceRoll, roll)
+	}
+
+	return diceRollIndex(roll), nil
+}
+
+// DicePassphrase builds a passphrase from physical dice rolls rather than the Generator's random
+// source, choosing one word per roll as numbered by DiceIndex. The word count is taken from the
+// number of rolls, so the options' count, word count, maximum length, random separators, digits,
+// symbols, and policy are ignored, while the separator, casing, word list, and word lengths are
+// honored.
+// ErrDiceCasing is returned for a casing chosen at random, which the rolls do not account for, and
+// ErrDiceWordList unless the word list contains exactly 7776 unique words of the allowed lengths
+// after casing is applied, as every roll must choose a distinct word.
+func DicePassphrase(rolls []string, opts PassphraseOptions) (string, error) {
+	if opts.Casing == PassphraseCasingRandomWord || opts.Casing == PassphraseCasingOneCapital {
+		return "", ErrDiceCasing
+	}
+
+	// Validate the options, taking the word count from the number of rolls.
+	opts.Count = 1
+	opts.WordCount = uint(len(rolls))
+	opts.MaxLength = 0
+	opts.RandomSeparators = ""
+	opts.Digits = 0
+	opts.Symbols = 0
+	opts.MinEntropy = 0
+	opts.Policy = Policy{}
+
+	wordSet, err := opts.wordSet()
+	if err != nil {
+		return "", err
+	}
+
+	// Ensure every roll maps to a unique word. The word set preserves the order of the word list
+	// when it has no duplicates.
+	if len(wordSet) != DiceWordListLength || len(opts.WordList) != DiceWordListLength {
+		return "", ErrDiceWordList
+	}
+
+	var (
+		b     strings.Builder
+		caser = newWordCaser(opts.Casing, opts.Language)
+	)
+
+	for i, roll := range rolls {
+		// Determine the word chosen by the roll.
+		wordIdx, err := DiceIndex(roll)
+		if err != nil {
+			return "", err
+		}
+
+		// Write the provided separator if this is not the first word in the passphrase.
+		if i > 0 {
+			b.WriteString(opts.Separator)
+		}
+
+		// Retrieve the word from the word set and write it to the passphrase, cased for its
+		// position.
+		b.WriteString(caser.caseWord(wordSet[wordIdx], uint(i), 0))
+	}
+
+	return b.String(), nil
+}
+
+// DiceEntropy returns the number of bits of entropy in a passphrase built from the given number of
+// rolls of fair dice.
+func DiceEntropy(rollCount uint) float64 {
+	return float64(rollCount) * math.Log2(DiceWordListLength)
+}
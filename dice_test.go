@@ -0,0 +1,167 @@
+package passgen
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiceIndex(t *testing.T) {
+	type testDef struct {
+		roll  string
+		index uint
+		valid bool
+	}
+
+	var tests = []testDef{
+		{"11111", 0, true},
+		{"11112", 1, true},
+		{"11121", 6, true},
+		{"66666", DiceWordListLength - 1, true},
+		{"1111", 0, false},
+		{"111111", 0, false},
+		{"11110", 0, false},
+		{"11117", 0, false},
+		{"1a111", 0, false},
+		{"", 0, false},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.roll,
+			func(t *testing.T) {
+				index, err := DiceIndex(test.roll)
+				if test.valid {
+					require.NoError(t, err)
+					require.Equal(t, test.index, index)
+				} else {
+					require.True(t, errors.Is(err, ErrInvalidDiceRoll))
+				}
+			},
+		)
+	}
+}
+
+func TestDicePassphrase(t *testing.T) {
+	type testReqs func(t *testing.T, passphrase string, err error)
+
+	type testDef struct {
+		name    string
+		rolls   []string
+		options PassphraseOptions
+
+		requirements testReqs
+	}
+
+	var tests = []testDef{
+		{
+			"EFF numbering",
+			[]string{"16655", "43215", "25364", "11111", "66666"},
+			DefaultPassphraseOptions(),
+
+			func(t *testing.T, passphrase string, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "contusion overhang enjoyer abacus zoom", passphrase)
+			},
+		},
+		{
+			"separator and casing",
+			[]string{"16655", "43215", "25364"},
+			PassphraseOptions{
+				Separator: PassphraseSeparatorDash,
+				Casing:    PassphraseCasingUpper,
+				WordList:  WordListDefault,
+			},
+
+			func(t *testing.T, passphrase string, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "CONTUSION-OVERHANG-ENJOYER", passphrase)
+			},
+		},
+		{
+			"camel case",
+			[]string{"16655", "43215", "25364"},
+			PassphraseOptions{
+				Separator: PassphraseSeparatorNone,
+				Casing:    PassphraseCasingCamelCase,
+				WordList:  WordListDefault,
+			},
+
+			func(t *testing.T, passphrase string, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "contusionOverhangEnjoyer", passphrase)
+			},
+		},
+		{
+			"random casing",
+			[]string{"16655", "43215", "25364"},
+			PassphraseOptions{
+				Separator: PassphraseSeparatorDefault,
+				Casing:    PassphraseCasingOneCapital,
+				WordList:  WordListDefault,
+			},
+
+			func(t *testing.T, passphrase string, err error) {
+				require.Empty(t, passphrase)
+				require.True(t, errors.Is(err, ErrDiceCasing))
+			},
+		},
+		{
+			"invalid roll",
+			[]string{"16655", "43215", "25367"},
+			DefaultPassphraseOptions(),
+
+			func(t *testing.T, passphrase string, err error) {
+				require.Empty(t, passphrase)
+				require.True(t, errors.Is(err, ErrInvalidDiceRoll))
+			},
+		},
+		{
+			"too few rolls",
+			[]string{"16655", "43215"},
+			DefaultPassphraseOptions(),
+
+			func(t *testing.T, passphrase string, err error) {
+				require.Empty(t, passphrase)
+				var rangeErr *RangeError
+				require.True(t, errors.As(err, &rangeErr))
+			},
+		},
+		{
+			"word list too small",
+			[]string{"16655", "43215", "25364"},
+			PassphraseOptions{Casing: PassphraseCasingNone, WordList: WordListDefault[1:]},
+
+			func(t *testing.T, passphrase string, err error) {
+				require.Empty(t, passphrase)
+				require.True(t, errors.Is(err, ErrDiceWordList))
+			},
+		},
+		{
+			"word list with duplicates",
+			[]string{"16655", "43215", "25364"},
+			PassphraseOptions{Casing: PassphraseCasingNone, WordList: append(append([]string{}, WordListDefault[1:]...), WordListDefault[1])},
+
+			func(t *testing.T, passphrase string, err error) {
+				require.Empty(t, passphrase)
+				require.True(t, errors.Is(err, ErrDiceWordList))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name,
+			func(t *testing.T) {
+				passphrase, err := DicePassphrase(test.rolls, test.options)
+				test.requirements(t, passphrase, err)
+			},
+		)
+	}
+}
+
+func TestDiceEntropy(t *testing.T) {
+	require.InDelta(t, 6*5*math.Log2(6), DiceEntropy(6), 1e-9)
+}
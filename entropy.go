@@ -0,0 +1,167 @@
+package passgen
+
+import "math"
+
+// log2Add returns log2(2^a + 2^b) without leaving logarithmic space.
+func log2Add(a, b float64) float64 {
+	if math.IsInf(a, -1) {
+		return b
+	}
+	if math.IsInf(b, -1) {
+		return a
+	}
+	if a < b {
+		a, b = b, a
+	}
+	return a + math.Log2(1+math.Exp2(b-a))
+}
+
+// log2Factorials returns a table of log2(i!) for every i in the range [0, n].
+func log2Factorials(n uint) []float64 {
+	table := make([]float64, n+1)
+
+	var i uint
+	for i = 1; i <= n; i++ {
+		table[i] = table[i-1] + math.Log2(float64(i))
+	}
+
+	return table
+}
+
+// log2RequiredCount returns log2 of the number of sequences of the given length which contain at
+// least mins[i] symbols drawn from each disjoint class of sizes[i] symbols, with any of the
+// remaining symbols filling the other positions.
+func log2RequiredCount(length uint, sizes []uint, mins []uint, remaining uint) float64 {
+	factorials := log2Factorials(length)
+	log2Binomial := func(n, k uint) float64 {
+		return factorials[n] - factorials[k] - factorials[n-k]
+	}
+
+	// ways[j] holds log2 of the number of ways the classes processed so far can occupy exactly j
+	// positions of the sequence.
+	ways := make([]float64, length+1)
+	for j := range ways {
+		ways[j] = math.Inf(-1)
+	}
+	ways[0] = 0
+
+	for i, size := range sizes {
+		next := make([]float64, length+1)
+		for j := range next {
+			next[j] = math.Inf(-1)
+		}
+
+		var j, k uint
+		for j = 0; j <= length; j++ {
+			if math.IsInf(ways[j], -1) {
+				continue
+			}
+
+			// Place k symbols of this class into the positions not yet occupied.
+			for k = mins[i]; j+k <= length; k++ {
+				next[j+k] = log2Add(next[j+k], ways[j]+log2Binomial(length-j, k)+float64(k)*math.Log2(float64(size)))
+			}
+		}
+
+		ways = next
+	}
+
+	// Fill the unoccupied positions with any of the remaining symbols.
+	total := math.Inf(-1)
+	var j uint
+	for j = 0; j <= length; j++ {
+		if math.IsInf(ways[j], -1) {
+			continue
+		}
+		if remaining == 0 {
+			if j == length {
+				total = log2Add(total, ways[j])
+			}
+			continue
+		}
+		total = log2Add(total, ways[j]+float64(length-j)*math.Log2(float64(remaining)))
+	}
+
+	return total
+}
+
+// log2RunLimitedCount returns log2 of the number of sequences of the given length, drawn from size
+// symbols, in which no symbol appears more than maxRun times in a row.
+func log2RunLimitedCount(length uint, size uint, maxRun uint) float64 {
+	// counts[n] holds log2 of the number of such sequences of length n. A sequence consists of a
+	// sequence ending in a different symbol, or nothing at all, followed by a final run of at most
+	// maxRun symbols.
+	counts := make([]float64, length+1)
+	for n := range counts {
+		counts[n] = math.Inf(-1)
+	}
+	counts[0] = 0
+
+	var n, run uint
+	for n = 1; n <= length; n++ {
+		for run = 1; run <= maxRun && run <= n; run++ {
+			if run == n {
+				counts[n] = log2Add(counts[n], math.Log2(float64(size)))
+				continue
+			}
+			counts[n] = log2Add(counts[n], counts[n-run]+math.Log2(float64(size)-1))
+		}
+	}
+
+	return counts[length]
+}
+
+// log2BoundedLengthCount returns log2 of the number of sequences of count items whose lengths sum
+// to no more than maxLength, where lengthCounts[l] is the number of distinct items of length l.
+func log2BoundedLengthCount(count uint, lengthCounts []uint, maxLength uint) float64 {
+	// The bound has no effect if even a sequence of the longest items meets it.
+	var total uint
+	for _, n := range lengthCounts {
+		total += n
+	}
+	if total == 0 {
+		return math.Inf(-1)
+	}
+	if maxLength >= count*uint(len(lengthCounts)-1) {
+		return float64(count) * math.Log2(float64(total))
+	}
+
+	// ways[j] holds log2 of the number of sequences of the items processed so far whose lengths sum
+	// to exactly j.
+	ways := make([]float64, maxLength+1)
+	for j := range ways {
+		ways[j] = math.Inf(-1)
+	}
+	ways[0] = 0
+
+	var i uint
+	for i = 0; i < count; i++ {
+		next := make([]float64, maxLength+1)
+		for j := range next {
+			next[j] = math.Inf(-1)
+		}
+
+		for j := range ways {
+			if math.IsInf(ways[j], -1) {
+				continue
+			}
+
+			// Append an item of each length which keeps the sum within the bound.
+			for l, n := range lengthCounts {
+				if n == 0 || j+l > int(maxLength) {
+					continue
+				}
+				next[j+l] = log2Add(next[j+l], ways[j]+math.Log2(float64(n)))
+			}
+		}
+
+		ways = next
+	}
+
+	result := math.Inf(-1)
+	for _, w := range ways {
+		result = log2Add(result, w)
+	}
+
+	return result
+}
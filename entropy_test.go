@@ -0,0 +1,192 @@
+package passgen
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLog2RequiredCount(t *testing.T) {
+	type testDef struct {
+		name      string
+		length    uint
+		sizes     []uint
+		mins      []uint
+		remaining uint
+	}
+
+	var tests = []testDef{
+		{"no classes", 6, nil, nil, 4},
+		{"single class", 6, []uint{2}, []uint{2}, 3},
+		{"multiple classes", 7, []uint{2, 3}, []uint{1, 2}, 2},
+		{"no remaining symbols", 5, []uint{1, 2}, []uint{2, 1}, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name,
+			func(t *testing.T) {
+				// Enumerate every sequence over the symbols, numbering the classes first.
+				var symbols []int
+				for class, size := range test.sizes {
+					var i uint
+					for i = 0; i < size; i++ {
+						symbols = append(symbols, class)
+					}
+				}
+				var i uint
+				for i = 0; i < test.remaining; i++ {
+					symbols = append(symbols, -1)
+				}
+
+				var count float64
+				sequence := make([]int, test.length)
+				var enumerate func(position uint)
+				enumerate = func(position uint) {
+					if position == test.length {
+						counts := make([]uint, len(test.sizes))
+						for _, class := range sequence {
+							if class >= 0 {
+								counts[class]++
+							}
+						}
+						for class, min := range test.mins {
+							if counts[class] < min {
+								return
+							}
+						}
+						count++
+						return
+					}
+					for _, class := range symbols {
+						sequence[position] = class
+						enumerate(position + 1)
+					}
+				}
+				enumerate(0)
+
+				require.InDelta(
+					t,
+					math.Log2(count),
+					log2RequiredCount(test.length, test.sizes, test.mins, test.remaining),
+					1e-9,
+				)
+			},
+		)
+	}
+}
+
+func TestLog2RunLimitedCount(t *testing.T) {
+	type testDef struct {
+		name   string
+		length uint
+		size   uint
+		maxRun uint
+	}
+
+	var tests = []testDef{
+		{"no repeats", 6, 3, 1},
+		{"short runs", 7, 2, 2},
+		{"unreachable limit", 4, 3, 5},
+		{"single symbol", 3, 1, 3},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name,
+			func(t *testing.T) {
+				// Enumerate every sequence over the symbols, tracking the run of the last symbol.
+				var count float64
+				var enumerate func(position uint, last uint, run uint)
+				enumerate = func(position uint, last uint, run uint) {
+					if run > test.maxRun {
+						return
+					}
+					if position == test.length {
+						count++
+						return
+					}
+					var symbol uint
+					for symbol = 0; symbol < test.size; symbol++ {
+						if position > 0 && symbol == last {
+							enumerate(position+1, symbol, run+1)
+						} else {
+							enumerate(position+1, symbol, 1)
+						}
+					}
+				}
+				enumerate(0, 0, 0)
+
+				require.InDelta(
+					t,
+					math.Log2(count),
+					log2RunLimitedCount(test.length, test.size, test.maxRun),
+					1e-9,
+				)
+			},
+		)
+	}
+
+	t.Run(
+		"nothing fits",
+		func(t *testing.T) {
+			require.True(t, math.IsInf(log2RunLimitedCount(4, 1, 3), -1))
+		},
+	)
+}
+
+func TestLog2BoundedLengthCount(t *testing.T) {
+	type testDef struct {
+		name         string
+		count        uint
+		lengthCounts []uint
+		maxLength    uint
+	}
+
+	var tests = []testDef{
+		{"unbounded", 3, []uint{0, 2, 3}, 6},
+		{"bounded", 3, []uint{0, 2, 3, 1}, 6},
+		{"tight bound", 4, []uint{0, 0, 2, 5, 1}, 9},
+		{"exact fit", 2, []uint{0, 1, 4}, 2},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name,
+			func(t *testing.T) {
+				// Enumerate every sequence of item lengths, weighting it by the items of each length.
+				var count float64
+				var enumerate func(position uint, length uint, ways float64)
+				enumerate = func(position uint, length uint, ways float64) {
+					if length > test.maxLength {
+						return
+					}
+					if position == test.count {
+						count += ways
+						return
+					}
+					for l, n := range test.lengthCounts {
+						enumerate(position+1, length+uint(l), ways*float64(n))
+					}
+				}
+				enumerate(0, 0, 1)
+
+				require.InDelta(
+					t,
+					math.Log2(count),
+					log2BoundedLengthCount(test.count, test.lengthCounts, test.maxLength),
+					1e-9,
+				)
+			},
+		)
+	}
+
+	t.Run(
+		"nothing fits",
+		func(t *testing.T) {
+			require.True(t, math.IsInf(log2BoundedLengthCount(3, []uint{0, 0, 3}, 5), -1))
+			require.True(t, math.IsInf(log2BoundedLengthCount(3, nil, 5), -1))
+		},
+	)
+}
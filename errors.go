@@ -0,0 +1,73 @@
+package passgen
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors returned when validating generator configurations.
+var (
+	ErrAlphabetTooSmall  = fmt.Errorf("alphabet must contain at least %d unique characters", AlphabetLengthMin)
+	ErrWordListTooSmall  = fmt.Errorf("word list must contain at least %d unique words", WordListLengthMin)
+	ErrInvalidCasing     = errors.New("invalid word casing")
+	ErrInvalidWordLength = errors.New("minimum word length must not exceed the maximum word length")
+	ErrUnknownWordList   = errors.New("unknown word list")
+	ErrWordListMissing   = errors.New("dice-numbered word list is missing a roll")
+
+	ErrInvalidEncoding = errors.New("invalid byte encoding")
+
+	ErrEntropyUnreachable = errors.New("entropy target cannot be met within the length limits")
+
+	ErrInvalidTokenPrefix = fmt.Errorf("token prefix must be 1 to %d alphanumeric characters", TokenPrefixLengthMax)
+	ErrTokenPrefix        = errors.New("token does not have the expected prefix")
+	ErrTokenFormat        = errors.New("token is malformed")
+	ErrTokenChecksum      = errors.New("token checksum does not match")
+
+	ErrInvalidMnemonicEntropy = fmt.Errorf("mnemonic entropy must be %d to %d bits in multiples of %d", MnemonicEntropyBitsMin, MnemonicEntropyBitsMax, MnemonicEntropyBitsMultiple)
+	ErrMnemonicWordList       = fmt.Errorf("mnemonic word list must contain exactly %d unique words", MnemonicWordListLength)
+	ErrMnemonicLength         = errors.New("mnemonic has an invalid number of words")
+	ErrMnemonicWord           = errors.New("mnemonic contains a word not in the word list")
+	ErrMnemonicChecksum       = errors.New("mnemonic checksum does not match")
+
+	ErrInvalidDiceRoll = fmt.Errorf("dice roll must be %d digits from 1 to %d", DiceRollsPerWord, DiceSides)
+	ErrDiceWordList    = fmt.Errorf("dice word list must contain exactly %d unique words", DiceWordListLength)
+	ErrDiceCasing      = errors.New("dice passphrases cannot use a casing chosen at random")
+
+	ErrDeriveMasterEmpty = errors.New("master secret must not be empty")
+	ErrDeriveSiteEmpty   = errors.New("site must not be empty")
+
+	ErrRequirementNotInAlphabet = errors.New("required character class has no characters in the alphabet")
+	ErrRequirementsOverlap      = errors.New("required character classes must not share characters")
+	ErrRequirementsExceedLength = errors.New("required character counts exceed the password length")
+	ErrRequirementsUnsatisfied  = errors.New("requirements are too unlikely to be met by random generation")
+
+	ErrInvalidPolicyLength            = errors.New("policy minimum length must not exceed the maximum length")
+	ErrPolicyLengthUnreachable        = errors.New("policy length limits exclude every generated length")
+	ErrPolicyClassNotInAlphabet       = errors.New("policy requires a character class with no allowed characters in the alphabet")
+	ErrPolicyForbidsAlphabet          = errors.New("policy forbids every character of the alphabet")
+	ErrPolicyRequirementsExceedLength = errors.New("policy required character count exceeds the generated length")
+	ErrPolicyUnsatisfied              = errors.New("policy is too unlikely to be met by random generation")
+)
+
+// RangeError is returned when a numeric parameter falls outside of its allowed bounds.
+type RangeError struct {
+	Parameter string // Name of the offending parameter.
+	Min       uint   // Smallest allowed value of the parameter.
+	Max       uint   // Largest allowed value of the parameter.
+}
+
+// Error implements the error interface.
+func (e *RangeError) Error() string {
+	return fmt.Sprintf("%s must be at least %d and at most %d", e.Parameter, e.Min, e.Max)
+}
+
+// WordListError is returned when a line of a word list cannot be parsed.
+type WordListError struct {
+	Line   uint   // Number of the offending line, beginning at one.
+	Reason string // Description of what is wrong with the line.
+}
+
+// Error implements the error interface.
+func (e *WordListError) Error() string {
+	return fmt.Sprintf("word list line %d: %s", e.Line, e.Reason)
+}
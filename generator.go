@@ -0,0 +1,24 @@
+package passgen
+
+import (
+	"crypto/rand"
+	"io"
+)
+
+// The default generator, used by the package-level generation functions, reads random data from
+// crypto/rand.
+var defaultGenerator = NewGenerator(rand.Reader)
+
+// Generator generates passwords and passphrases using random data read from its entropy source.
+// A Generator is safe for concurrent use if its entropy source is.
+type Generator struct {
+	source io.Reader // Entropy source random data is read from.
+}
+
+// NewGenerator constructs a Generator which reads random data from the provided entropy source.
+// The source must produce uniformly distributed random bytes, such as crypto/rand.Reader.
+func NewGenerator(source io.Reader) *Generator {
+	return &Generator{
+		source: source,
+	}
+}
@@ -0,0 +1,86 @@
+package passgen
+
+import (
+	"bytes"
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerator(t *testing.T) {
+	t.Run(
+		"identical sources produce identical output",
+		func(t *testing.T) {
+			first := NewGenerator(rand.New(rand.NewSource(1)))
+			second := NewGenerator(rand.New(rand.NewSource(1)))
+
+			firstPasswords, err := first.Passwords(PasswordCountDefault, PasswordLengthDefault, AlphabetDefault)
+			require.NoError(t, err)
+			secondPasswords, err := second.Passwords(PasswordCountDefault, PasswordLengthDefault, AlphabetDefault)
+			require.NoError(t, err)
+			require.Equal(t, firstPasswords, secondPasswords)
+
+			firstPassphrases, err := first.Passphrases(
+				PassphraseCountDefault,
+				PassphraseWordCountDefault,
+				PassphraseSeparatorDefault,
+				PassphraseCasingDefault,
+				WordListDefault,
+			)
+			require.NoError(t, err)
+			secondPassphrases, err := second.Passphrases(
+				PassphraseCountDefault,
+				PassphraseWordCountDefault,
+				PassphraseSeparatorDefault,
+				PassphraseCasingDefault,
+				WordListDefault,
+			)
+			require.NoError(t, err)
+			require.Equal(t, firstPassphrases, secondPassphrases)
+		},
+	)
+
+	t.Run(
+		"concurrent generators with independent sources",
+		func(t *testing.T) {
+			// Collect the results of each goroutine, as only the test goroutine may fail the test.
+			type generated struct {
+				passwords []string
+				err       error
+			}
+
+			var (
+				wg      sync.WaitGroup
+				results = make(chan generated, 8)
+			)
+			for i := 0; i < 8; i++ {
+				wg.Add(1)
+				go func(seed int64) {
+					defer wg.Done()
+					generator := NewGenerator(rand.New(rand.NewSource(seed)))
+					passwords, err := generator.Passwords(PasswordCountMax, PasswordLengthDefault, AlphabetDefault)
+					results <- generated{passwords, err}
+				}(int64(i))
+			}
+			wg.Wait()
+			close(results)
+
+			for result := range results {
+				require.NoError(t, result.err)
+				require.Len(t, result.passwords, PasswordCountMax)
+			}
+		},
+	)
+
+	t.Run(
+		"exhausted source",
+		func(t *testing.T) {
+			generator := NewGenerator(bytes.NewReader([]byte{0xff}))
+			passwords, err := generator.Passwords(PasswordCountDefault, PasswordLengthDefault, AlphabetDefault)
+			require.Empty(t, passwords)
+			require.Error(t, err)
+		},
+	)
+}
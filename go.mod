@@ -1,9 +1,12 @@
 module github.com/decentral1se/passgen
 
-go 1.14
+go 1.16
 
 require (
 	github.com/spf13/cobra v1.0.0
 	github.com/spf13/pflag v1.0.5 // indirect
 	github.com/stretchr/testify v1.6.1
+	golang.org/x/sys v0.10.0 // indirect
+	golang.org/x/term v0.10.0
+	golang.org/x/text v0.13.0
 )
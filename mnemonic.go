@@ -0,0 +1,217 @@
+package passgen
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Number of bits encoded by each word of a mnemonic.
+const mnemonicBitsPerWord = 11
+
+// MnemonicOptions configures the generation of BIP39 mnemonics. A mnemonic encodes its entropy
+// along with a checksum derived from the SHA-256 hash of the entropy, so that a mistyped or
+// misremembered word can be detected when the mnemonic is decoded.
+type MnemonicOptions struct {
+	Count       uint     // Number of mnemonics to generate.
+	EntropyBits uint     // Bits of entropy encoded in each generated mnemonic.
+	WordList    []string // List of exactly 2048 words to encode mnemonics with.
+}
+
+// DefaultMnemonicOptions returns mnemonic options populated with the package defaults.
+func DefaultMnemonicOptions() MnemonicOptions {
+	return MnemonicOptions{
+		Count:       MnemonicCountDefault,
+		EntropyBits: MnemonicEntropyBitsDefault,
+		WordList:    WordListBIP39English,
+	}
+}
+
+// Validate checks the options against the package limits. A *RangeError is returned for an out of
+// bounds count, ErrInvalidMnemonicEntropy for an unsupported number of entropy bits, and
+// ErrMnemonicWordList for a word list without exactly 2048 unique words.
+func (o MnemonicOptions) Validate() error {
+	// Validate the supplied count parameter.
+	if o.Count < MnemonicCountMin || o.Count > MnemonicCountMax {
+		return &RangeError{"count", MnemonicCountMin, MnemonicCountMax}
+	}
+
+	// Validate the supplied entropy parameter.
+	if !validMnemonicEntropyBits(o.EntropyBits) {
+		return ErrInvalidMnemonicEntropy
+	}
+
+	// Validate the provided word list.
+	_, err := mnemonicWordIndices(o.WordList)
+	return err
+}
+
+// Entropy returns the number of bits of entropy in each mnemonic generated with the options. The
+// checksum words carry no additional entropy.
+func (o MnemonicOptions) Entropy() (float64, error) {
+	err := o.Validate()
+	if err != nil {
+		return 0, err
+	}
+
+	return float64(o.EntropyBits), nil
+}
+
+// WordCount returns the number of words in each mnemonic generated with the options.
+func (o MnemonicOptions) WordCount() uint {
+	return (o.EntropyBits + o.EntropyBits/MnemonicEntropyBitsMultiple) / mnemonicBitsPerWord
+}
+
+// validMnemonicEntropyBits reports whether the number of bits may be encoded as a mnemonic.
+func validMnemonicEntropyBits(entropyBits uint) bool {
+	return entropyBits >= MnemonicEntropyBitsMin &&
+		entropyBits <= MnemonicEntropyBitsMax &&
+		entropyBits%MnemonicEntropyBitsMultiple == 0
+}
+
+// mnemonicWordIndices returns the index of each word within the word list, ensuring the list
+// contains exactly 2048 unique words.
+func mnemonicWordIndices(wordList []string) (map[string]uint, error) {
+	if len(wordList) != MnemonicWordListLength {
+		return nil, ErrMnemonicWordList
+	}
+
+	indices := make(map[string]uint, len(wordList))
+	for i, word := range wordList {
+		if _, ok := indices[word]; ok {
+			return nil, ErrMnemonicWordList
+		}
+		indices[word] = uint(i)
+	}
+
+	return indices, nil
+}
+
+// mnemonicChecksum returns the checksum byte of the entropy, of which only the leading
+// len(entropy)/4 bits are used.
+func mnemonicChecksum(entropy []byte) byte {
+	hash := sha256.Sum256(entropy)
+	return hash[0]
+}
+
+// EncodeMnemonic encodes the entropy, which must be 16 to 32 bytes in multiples of 4, as a
+// mnemonic of words from the word list separated by spaces.
+func EncodeMnemonic(entropy []byte, wordList []string) (string, error) {
+	// Validate the provided entropy and word list.
+	if !validMnemonicEntropyBits(uint(len(entropy)) * 8) {
+		return "", ErrInvalidMnemonicEntropy
+	}
+	if _, err := mnemonicWordIndices(wordList); err != nil {
+		return "", err
+	}
+
+	return encodeMnemonic(entropy, wordList), nil
+}
+
+// encodeMnemonic encodes the entropy as a mnemonic. The entropy and word list must already be
+// validated.
+func encodeMnemonic(entropy []byte, wordList []string) string {
+	// Append the checksum to the entropy. Its unused bits are never read.
+	data := append(append([]byte{}, entropy...), mnemonicChecksum(entropy))
+	wordCount := uint(len(entropy)) * 8 * 33 / 32 / mnemonicBitsPerWord
+
+	words := make([]string, wordCount)
+	for i := range words {
+		// Read the next group of bits as an index into the word list.
+		var idx uint
+		for j := uint(0); j < mnemonicBitsPerWord; j++ {
+			bitIdx := uint(i)*mnemonicBitsPerWord + j
+			idx = idx<<1 | uint(data[bitIdx/8]>>(7-bitIdx%8))&1
+		}
+
+		words[i] = wordList[idx]
+	}
+
+	return strings.Join(words, " ")
+}
+
+// DecodeMnemonic decodes a mnemonic of words from the word list separated by whitespace, returning
+// the entropy it encodes. ErrMnemonicLength is returned for a mnemonic with an invalid number of
+// words, ErrMnemonicWord for a mnemonic containing an unknown word, and ErrMnemonicChecksum for a
+// mnemonic whose checksum does not match its entropy, such as one containing a mistyped word.
+func DecodeMnemonic(mnemonic string, wordList []string) ([]byte, error) {
+	indices, err := mnemonicWordIndices(wordList)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ensure the mnemonic has a word count corresponding to a valid amount of entropy.
+	words := strings.Fields(mnemonic)
+	totalBits := uint(len(words)) * mnemonicBitsPerWord
+	checksumBits := totalBits / 33
+	if totalBits%33 != 0 || !validMnemonicEntropyBits(totalBits-checksumBits) {
+		return nil, ErrMnemonicLength
+	}
+
+	// Write the bits encoded by each word, including the checksum, to a buffer.
+	data := make([]byte, (totalBits+7)/8)
+	for i, word := range words {
+		idx, ok := indices[word]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrMnemonicWord, word)
+		}
+
+		for j := uint(0); j < mnemonicBitsPerWord; j++ {
+			bitIdx := uint(i)*mnemonicBitsPerWord + j
+			bit := byte(idx>>(mnemonicBitsPerWord-1-j)) & 1
+			data[bitIdx/8] |= bit << (7 - bitIdx%8)
+		}
+	}
+
+	// Ensure the checksum matches the entropy.
+	entropy := data[:(totalBits-checksumBits)/8]
+	checksum := data[len(entropy)]
+	mask := byte(0xff) << (8 - checksumBits)
+	if mnemonicChecksum(entropy)&mask != checksum&mask {
+		return nil, ErrMnemonicChecksum
+	}
+
+	return entropy, nil
+}
+
+// VerifyMnemonic checks that the mnemonic is made of words from the word list and that its
+// checksum matches, returning the same errors as DecodeMnemonic.
+func VerifyMnemonic(mnemonic string, wordList []string) error {
+	_, err := DecodeMnemonic(mnemonic, wordList)
+	return err
+}
+
+// GenerateMnemonics generates random mnemonics based on the provided options, using the default
+// Generator backed by crypto/rand.
+func GenerateMnemonics(opts MnemonicOptions) ([]string, error) {
+	return defaultGenerator.Mnemonics(opts)
+}
+
+// Mnemonics generates random mnemonics based on the provided options.
+func (g *Generator) Mnemonics(opts MnemonicOptions) (mnemonics []string, err error) {
+	// Validate the options.
+	err = opts.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		i       uint                               // Mnemonic counter.
+		entropy = make([]byte, opts.EntropyBits/8) // Byte buffer for random data encoded by mnemonics.
+	)
+
+	for i = 0; i < opts.Count; i++ {
+		// Read the random data directly from the source.
+		_, err = io.ReadFull(g.source, entropy)
+		if err != nil {
+			return nil, err
+		}
+
+		// Encode the random data and its checksum as words, appending the mnemonic to the return
+		// list.
+		mnemonics = append(mnemonics, encodeMnemonic(entropy, opts.WordList))
+	}
+
+	return
+}
@@ -0,0 +1,264 @@
+package passgen
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateMnemonics(t *testing.T) {
+	type testReqs func(t *testing.T, mnemonics []string, err error)
+
+	type testDef struct {
+		name    string
+		options MnemonicOptions
+
+		requirements testReqs
+	}
+
+	var tests = []testDef{
+		{
+			"rational defaults",
+			DefaultMnemonicOptions(),
+
+			func(t *testing.T, mnemonics []string, err error) {
+				require.NoError(t, err)
+				require.Len(t, mnemonics, MnemonicCountDefault)
+				for _, mnemonic := range mnemonics {
+					require.Len(t, strings.Fields(mnemonic), 12)
+					require.NoError(t, VerifyMnemonic(mnemonic, WordListBIP39English))
+				}
+			},
+		},
+		{
+			"non-default parameters",
+			MnemonicOptions{Count: MnemonicCountMax, EntropyBits: MnemonicEntropyBitsMax, WordList: WordListBIP39English},
+
+			func(t *testing.T, mnemonics []string, err error) {
+				require.NoError(t, err)
+				require.Len(t, mnemonics, MnemonicCountMax)
+				for _, mnemonic := range mnemonics {
+					require.Len(t, strings.Fields(mnemonic), 24)
+					entropy, err := DecodeMnemonic(mnemonic, WordListBIP39English)
+					require.NoError(t, err)
+					require.Len(t, entropy, MnemonicEntropyBitsMax/8)
+				}
+			},
+		},
+		{
+			"count too large",
+			MnemonicOptions{Count: MnemonicCountMax + 1, EntropyBits: MnemonicEntropyBitsDefault, WordList: WordListBIP39English},
+
+			func(t *testing.T, mnemonics []string, err error) {
+				require.Empty(t, mnemonics)
+				var rangeErr *RangeError
+				require.True(t, errors.As(err, &rangeErr))
+			},
+		},
+		{
+			"entropy too small",
+			MnemonicOptions{Count: MnemonicCountDefault, EntropyBits: MnemonicEntropyBitsMin - MnemonicEntropyBitsMultiple, WordList: WordListBIP39English},
+
+			func(t *testing.T, mnemonics []string, err error) {
+				require.Empty(t, mnemonics)
+				require.True(t, errors.Is(err, ErrInvalidMnemonicEntropy))
+			},
+		},
+		{
+			"entropy not a multiple of 32 bits",
+			MnemonicOptions{Count: MnemonicCountDefault, EntropyBits: MnemonicEntropyBitsMin + 8, WordList: WordListBIP39English},
+
+			func(t *testing.T, mnemonics []string, err error) {
+				require.Empty(t, mnemonics)
+				require.True(t, errors.Is(err, ErrInvalidMnemonicEntropy))
+			},
+		},
+		{
+			"word list too small",
+			MnemonicOptions{Count: MnemonicCountDefault, EntropyBits: MnemonicEntropyBitsDefault, WordList: WordListDefault},
+
+			func(t *testing.T, mnemonics []string, err error) {
+				require.Empty(t, mnemonics)
+				require.True(t, errors.Is(err, ErrMnemonicWordList))
+			},
+		},
+		{
+			"word list with duplicates",
+			MnemonicOptions{
+				Count:       MnemonicCountDefault,
+				EntropyBits: MnemonicEntropyBitsDefault,
+				WordList:    append(append([]string{}, WordListBIP39English[1:]...), WordListBIP39English[1]),
+			},
+
+			func(t *testing.T, mnemonics []string, err error) {
+				require.Empty(t, mnemonics)
+				require.True(t, errors.Is(err, ErrMnemonicWordList))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name,
+			func(t *testing.T) {
+				mnemonics, err := GenerateMnemonics(test.options)
+				test.requirements(t, mnemonics, err)
+			},
+		)
+	}
+
+	t.Run(
+		"random source EOF",
+		func(t *testing.T) {
+			mnemonics, err := NewGenerator(new(bytes.Reader)).Mnemonics(DefaultMnemonicOptions())
+			require.Empty(t, mnemonics)
+			require.Error(t, err)
+		},
+	)
+}
+
+func TestMnemonicVectors(t *testing.T) {
+	require.Len(t, WordListBIP39English, MnemonicWordListLength)
+
+	// Test vectors published alongside BIP39.
+	type testDef struct {
+		entropy  string
+		mnemonic string
+	}
+
+	var tests = []testDef{
+		{
+			"00000000000000000000000000000000",
+			"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+		},
+		{
+			"7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f",
+			"legal winner thank year wave sausage worth useful legal winner thank yellow",
+		},
+		{
+			"80808080808080808080808080808080",
+			"letter advice cage absurd amount doctor acoustic avoid letter advice cage above",
+		},
+		{
+			"ffffffffffffffffffffffffffffffff",
+			"zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo wrong",
+		},
+		{
+			"9e885d952ad362caeb4efe34a8e91bd2",
+			"ozone drill grab fiber curtain grace pudding thank cruise elder eight picnic",
+		},
+		{
+			"0000000000000000000000000000000000000000000000000000000000000000",
+			strings.Repeat("abandon ", 23) + "art",
+		},
+		{
+			"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+			strings.Repeat("zoo ", 23) + "vote",
+		},
+		{
+			"68a79eaca2324873eacc50cb9c6eca8cc68ea5d936f98787c60c7ebc74e6ce7c",
+			"hamster diagram private dutch cause delay private meat slide toddler razor book happy fancy gospel tennis maple dilemma loan word shrug inflict delay length",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.entropy,
+			func(t *testing.T) {
+				entropy, err := hex.DecodeString(test.entropy)
+				require.NoError(t, err)
+
+				// Ensure the entropy encodes to the expected mnemonic.
+				mnemonic, err := EncodeMnemonic(entropy, WordListBIP39English)
+				require.NoError(t, err)
+				require.Equal(t, test.mnemonic, mnemonic)
+
+				// Ensure the mnemonic decodes to the original entropy.
+				decoded, err := DecodeMnemonic(test.mnemonic, WordListBIP39English)
+				require.NoError(t, err)
+				require.Equal(t, entropy, decoded)
+
+				// Ensure generation from the same random data produces the same mnemonic.
+				mnemonics, err := NewGenerator(bytes.NewReader(entropy)).Mnemonics(MnemonicOptions{
+					Count:       1,
+					EntropyBits: uint(len(entropy)) * 8,
+					WordList:    WordListBIP39English,
+				})
+				require.NoError(t, err)
+				require.Equal(t, []string{test.mnemonic}, mnemonics)
+			},
+		)
+	}
+}
+
+func TestDecodeMnemonic(t *testing.T) {
+	type testDef struct {
+		name     string
+		mnemonic string
+		err      error
+	}
+
+	var tests = []testDef{
+		{
+			"extra whitespace",
+			"  legal winner thank year wave sausage\tworth useful legal winner thank yellow\n",
+			nil,
+		},
+		{
+			"mistyped word",
+			"legal winner thank year wave sausage worth useful legal winner thank zoo",
+			ErrMnemonicChecksum,
+		},
+		{
+			"swapped words",
+			"winner legal thank year wave sausage worth useful legal winner thank yellow",
+			ErrMnemonicChecksum,
+		},
+		{
+			"unknown word",
+			"legal winner thank year wave sausage worth useful legal winner thank yelow",
+			ErrMnemonicWord,
+		},
+		{
+			"missing word",
+			"legal winner thank year wave sausage worth useful legal winner thank",
+			ErrMnemonicLength,
+		},
+		{
+			"too many words",
+			strings.Repeat("zoo ", 27) + "zoo",
+			ErrMnemonicLength,
+		},
+		{
+			"empty mnemonic",
+			"",
+			ErrMnemonicLength,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name,
+			func(t *testing.T) {
+				err := VerifyMnemonic(test.mnemonic, WordListBIP39English)
+				if test.err == nil {
+					require.NoError(t, err)
+				} else {
+					require.True(t, errors.Is(err, test.err))
+				}
+			},
+		)
+	}
+
+	t.Run(
+		"invalid entropy length",
+		func(t *testing.T) {
+			_, err := EncodeMnemonic(make([]byte, 15), WordListBIP39English)
+			require.True(t, errors.Is(err, ErrInvalidMnemonicEntropy))
+		},
+	)
+}
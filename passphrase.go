@@ -1,135 +1,682 @@
 package passgen
 
 import (
-	"fmt"
-	"io"
 	"math"
+	"math/bits"
 	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
 )
 
 // PassphraseCasing represents the casing of each word within a passphrase.
 type PassphraseCasing uint8
 
-// GeneratePassphrases generates random passphrases based on the configuration provided by the user.
-func GeneratePassphrases(
-	count uint, // Number of passphrases to generate.
-	wordCount uint, // Length, in words, of each generated passphrase.
-	separator rune, // Passphrase word separator.
-	casing PassphraseCasing, // Passphrase word casing.
-	wordList []string, // List of words to pull passphrase words from.
-) (
-	passphrases []string, // Generated passphrases.
-	err error, // Possible error encountered during passphrase generation.
-) {
+// wordCaser applies a passphrase casing to words following the case mappings of a language. The
+// case mappings operate on whole strings rather than single characters, so words may change length
+// when cased, as "straße" does in uppercase.
+type wordCaser struct {
+	casing PassphraseCasing // Passphrase word casing.
+
+	lower cases.Caser // Lowercase mapping of the language.
+	upper cases.Caser // Uppercase mapping of the language.
+	title cases.Caser // Title case mapping of the language.
+}
+
+// newWordCaser returns a wordCaser applying the casing with the case mappings of the language.
+func newWordCaser(casing PassphraseCasing, tag language.Tag) *wordCaser {
+	return &wordCaser{
+		casing: casing,
+
+		lower: cases.Lower(tag),
+		upper: cases.Upper(tag),
+		title: cases.Title(tag),
+	}
+}
+
+// forms returns the forms of the word added to the word set under the casing, or nil if the word
+// must be left out.
+//
+// A random casing of each word adds every distinct casing of it to the word set, so choosing a
+// word from the set also chooses its casing. Casings which depend on the position of a word add
+// the lowercase form, which caseWord cases when the passphrase is written. Words whose other
+// casings change their length are left out so that the maximum length holds, as are words with no
+// title case of their own when a single word is capitalized, which would hide the capital.
+func (c *wordCaser) forms(word string) []string {
+	switch c.casing {
+	case PassphraseCasingLower:
+		return []string{c.lower.String(word)}
+	case PassphraseCasingUpper:
+		return []string{c.upper.String(word)}
+	case PassphraseCasingTitle:
+		return []string{c.title.String(word)}
+	case PassphraseCasingRandomWord:
+		return []string{c.lower.String(word), c.upper.String(word), c.title.String(word)}
+	case PassphraseCasingCamelCase, PassphraseCasingAlternating, PassphraseCasingOneCapital:
+		lower := c.lower.String(word)
+		cased := c.caseWord(lower, 1, 1)
+		if utf8.RuneCountInString(cased) != utf8.RuneCountInString(lower) ||
+			(c.casing == PassphraseCasingOneCapital && cased == lower) {
+			return nil
+		}
+		return []string{lower}
+	default:
+		return []string{word}
+	}
+}
+
+// caseWord applies the casing to a word of the word set at the given position of a passphrase,
+// where capital is the position of the word capitalized by PassphraseCasingOneCapital. Casings
+// which do not depend on the position return the word unchanged, as forms has already cased it.
+func (c *wordCaser) caseWord(word string, position uint, capital uint) string {
+	switch {
+	case c.casing == PassphraseCasingCamelCase && position > 0:
+		return c.title.String(word)
+	case c.casing == PassphraseCasingAlternating && position%2 == 1:
+		return c.upper.String(word)
+	case c.casing == PassphraseCasingOneCapital && position == capital:
+		return c.title.String(word)
+	default:
+		return word
+	}
+}
+
+// PassphraseOptions configures the generation of passphrases.
+type PassphraseOptions struct {
+	Count     uint             // Number of passphrases to generate.
+	WordCount uint             // Length, in words, of each generated passphrase.
+	Separator string           // Passphrase word separator, which may be empty or several characters.
+	Casing    PassphraseCasing // Passphrase word casing.
+	WordList  []string         // List of words to pull passphrase words from.
+	Language  language.Tag     // Language whose case mappings are used, or the zero value for language-neutral casing.
+
+	RandomSeparators string // If not empty, characters each separator is independently drawn from in place of Separator.
+
+	MinWordLength uint // If positive, the fewest characters allowed in each passphrase word.
+	MaxWordLength uint // If positive, the most characters allowed in each passphrase word.
+	MaxLength     uint // If positive, the most characters allowed in each passphrase, separators included.
+
+	Digits  uint // Number of digits from AlphabetNumeric to inject into each passphrase.
+	Symbols uint // Number of symbols from AlphabetSpecial to inject into each passphrase.
+
+	MinEntropy float64 // If positive, the entropy target used to choose the word count.
+
+	Policy Policy // Policy each passphrase must comply with.
+}
+
+// DefaultPassphraseOptions returns passphrase options populated with the package defaults.
+func DefaultPassphraseOptions() PassphraseOptions {
+	return PassphraseOptions{
+		Count:     PassphraseCountDefault,
+		WordCount: PassphraseWordCountDefault,
+		Separator: PassphraseSeparatorDefault,
+		Casing:    PassphraseCasingDefault,
+		WordList:  WordListDefault,
+	}
+}
+
+// Validate reports whether the options can generate passphrases, returning one of the errors in
+// errors.go if not.
+func (o PassphraseOptions) Validate() error {
+	o, err := o.Resolve()
+	if err != nil {
+		return err
+	}
+
+	_, err = o.wordSet()
+	return err
+}
+
+// Resolve returns a copy of the options with the word count set to the fewest words which meet the
+// entropy target, taking the maximum length into account. Options without an entropy target are
+// returned unchanged.
+func (o PassphraseOptions) Resolve() (PassphraseOptions, error) {
+	if !(o.MinEntropy > 0) {
+		return o, nil
+	}
+
+	// Validate the provided word lengths and word list.
+	if !o.validWordLengths() {
+		return o, ErrInvalidWordLength
+	}
+	wordSet := o.uniqueWords()
+	if len(wordSet) < WordListLengthMin {
+		return o, ErrWordListTooSmall
+	}
+
+	// Search for the fewest words which meet the target. Each additional word raises the entropy
+	// unless it pushes passphrases beyond the maximum length.
+	for o.WordCount = PassphraseWordCountMin; o.WordCount <= PassphraseWordCountMax; o.WordCount++ {
+		entropy := o.entropy(wordSet)
+		if entropy >= o.MinEntropy {
+			return o, nil
+		}
+
+		// Longer passphrases will not fit either once no passphrase fits.
+		if math.IsInf(entropy, -1) {
+			break
+		}
+	}
+
+	return o, ErrEntropyUnreachable
+}
+
+// Entropy returns the number of bits of entropy in each passphrase generated with the options,
+// accounting for random casings, the positions of injected digits and symbols, and the passphrases
+// discarded for exceeding the maximum length but not those discarded by the policy.
+func (o PassphraseOptions) Entropy() (float64, error) {
+	o, err := o.Resolve()
+	if err != nil {
+		return 0, err
+	}
+
+	wordSet, err := o.wordSet()
+	if err != nil {
+		return 0, err
+	}
+
+	return o.entropy(wordSet), nil
+}
+
+// entropy calculates the entropy of passphrases drawn from the word set.
+func (o PassphraseOptions) entropy(wordSet []string) float64 {
+	return o.wordsEntropy(wordSet) + o.casingEntropy() + o.separatorEntropy() + o.injectionEntropy()
+}
+
+// casingEntropy calculates the entropy of the choice of which word of passphrases to capitalize.
+// The entropy of words cased independently at random is counted by the word set, which holds every
+// casing of each word.
+func (o PassphraseOptions) casingEntropy() float64 {
+	if o.Casing != PassphraseCasingOneCapital {
+		return 0
+	}
+
+	return math.Log2(float64(o.WordCount))
+}
+
+// wordsEntropy calculates the entropy of the words of passphrases drawn from the word set.
+func (o PassphraseOptions) wordsEntropy(wordSet []string) float64 {
+	// Without a maximum length, every word is chosen independently from the whole word set.
+	if o.MaxLength == 0 {
+		return float64(o.WordCount) * math.Log2(float64(len(wordSet)))
+	}
+
+	// Otherwise, count the passphrases whose words fit within the length left by the separators and
+	// injected characters.
+	separatorsLength := (o.WordCount-1)*o.separatorLength() + o.Digits + o.Symbols
+	if separatorsLength > o.MaxLength {
+		return math.Inf(-1)
+	}
+
+	var lengthCounts []uint
+	for _, word := range wordSet {
+		length := utf8.RuneCountInString(word)
+		for len(lengthCounts) <= length {
+			lengthCounts = append(lengthCounts, 0)
+		}
+		lengthCounts[length]++
+	}
+
+	return log2BoundedLengthCount(o.WordCount, lengthCounts, o.MaxLength-separatorsLength)
+}
+
+// separatorEntropy calculates the entropy of the separators of passphrases, which is only positive
+// when each is drawn at random.
+func (o PassphraseOptions) separatorEntropy() float64 {
+	if o.RandomSeparators == "" {
+		return 0
+	}
+
+	return float64(o.WordCount-1) * math.Log2(float64(len(uniqueChars(o.RandomSeparators))))
+}
+
+// separatorLength returns the length, in characters, of each separator of passphrases.
+func (o PassphraseOptions) separatorLength() uint {
+	if o.RandomSeparators != "" {
+		return 1
+	}
+
+	return uint(utf8.RuneCountInString(o.Separator))
+}
+
+// injectionEntropy calculates the entropy of the digits and symbols injected into passphrases,
+// including the choice of where each is placed. Each injected character is placed at the start of
+// the passphrase or attached to the end of a word, so a passphrase is an arrangement of its words,
+// digits, and symbols with the words kept in order.
+func (o PassphraseOptions) injectionEntropy() float64 {
+	if o.Digits == 0 && o.Symbols == 0 {
+		return 0
+	}
+
+	// Count the distinct arrangements, which is the multinomial coefficient of the word, digit, and
+	// symbol counts, then the choice of each character.
+	factorials := log2Factorials(o.WordCount + o.Digits + o.Symbols)
+	arrangements := factorials[o.WordCount+o.Digits+o.Symbols] -
+		factorials[o.WordCount] - factorials[o.Digits] - factorials[o.Symbols]
+
+	return arrangements +
+		float64(o.Digits)*math.Log2(float64(len(AlphabetNumeric))) +
+		float64(o.Symbols)*math.Log2(float64(len(AlphabetSpecial)))
+}
+
+// wordSet validates the options and returns the deduplicated word list after casing is applied and
+// words of disallowed lengths or containing injected characters or random separators are removed,
+// preserving the order in which words first appear.
+func (o PassphraseOptions) wordSet() ([]string, error) {
 	// Validate the supplied count parameter.
-	if count < PassphraseCountMin || count > PassphraseCountMax {
-		return nil, fmt.Errorf("count must be at least %d and at most %d", PassphraseCountMin, PassphraseCountMax)
+	if o.Count < PassphraseCountMin || o.Count > PassphraseCountMax {
+		return nil, &RangeError{"count", PassphraseCountMin, PassphraseCountMax}
 	}
 
 	// Validate the supplied word count parameter.
-	if wordCount < PassphraseWordCountMin || wordCount > PassphraseWordCountMax {
-		return nil, fmt.Errorf("word count must be at least %d and at most %d", PassphraseWordCountMin, PassphraseWordCountMax)
+	if o.WordCount < PassphraseWordCountMin || o.WordCount > PassphraseWordCountMax {
+		return nil, &RangeError{"word count", PassphraseWordCountMin, PassphraseWordCountMax}
+	}
+
+	// Validate the supplied digits and symbols parameters.
+	if o.Digits > PassphraseDigitsMax {
+		return nil, &RangeError{"digits", 0, PassphraseDigitsMax}
+	}
+	if o.Symbols > PassphraseSymbolsMax {
+		return nil, &RangeError{"symbols", 0, PassphraseSymbolsMax}
 	}
 
 	// Validate the supplied casing parameter.
-	switch casing {
-	case PassphraseCasingLower, PassphraseCasingUpper, PassphraseCasingTitle, PassphraseCasingNone:
+	switch o.Casing {
+	case PassphraseCasingLower, PassphraseCasingUpper, PassphraseCasingTitle, PassphraseCasingNone,
+		PassphraseCasingRandomWord, PassphraseCasingCamelCase, PassphraseCasingAlternating, PassphraseCasingOneCapital:
 		break
 	default:
-		return nil, fmt.Errorf("invalid word casing")
+		return nil, ErrInvalidCasing
+	}
+
+	// Validate the supplied word length parameters.
+	if !o.validWordLengths() {
+		return nil, ErrInvalidWordLength
+	}
+
+	// Validate the provided policy.
+	err := o.Policy.Validate()
+	if err != nil {
+		return nil, err
 	}
 
-	// Deduplicate the provided word list.
+	// Validate the provided word list.
+	wordSet := o.uniqueWords()
+	if len(wordSet) < WordListLengthMin {
+		return nil, ErrWordListTooSmall
+	}
+
+	// Ensure passphrases are likely enough to fit within the maximum length that the attempt limit
+	// will not be reached.
+	if o.MaxLength > 0 {
+		log2Acceptance := o.wordsEntropy(wordSet) - float64(o.WordCount)*math.Log2(float64(len(wordSet)))
+		if log2Acceptance < 4-math.Log2(GenerationAttemptsMax) {
+			return nil, ErrRequirementsUnsatisfied
+		}
+	}
+
+	// Ensure passphrases drawn from the word set can comply with the policy.
+	err = o.validatePolicy(wordSet)
+	if err != nil {
+		return nil, err
+	}
+
+	return wordSet, nil
+}
+
+// validatePolicy checks the policy against the lengths and characters of passphrases drawn from the
+// word set, returning one of the policy errors for a policy no passphrase complies with. Words whose
+// every written form contains a forbidden character never appear in a compliant passphrase, so
+// only the remaining words are counted.
+func (o PassphraseOptions) validatePolicy(wordSet []string) error {
+	caser := newWordCaser(o.Casing, o.Language)
+
+	var (
+		usable        bool            // Whether any word may appear.
+		minWordLength uint            // Fewest characters in a word which may appear.
+		maxWordLength uint            // Most characters in a word which may appear.
+		wordChars     strings.Builder // Characters of the words which may appear.
+	)
+	for _, word := range wordSet {
+		// Each word is written as it is in the word set, or cased for its position.
+		for _, form := range []string{word, caser.caseWord(word, 1, 1)} {
+			if strings.ContainsAny(form, o.Policy.ForbiddenCharacters) {
+				continue
+			}
+
+			length := uint(utf8.RuneCountInString(form))
+			if !usable || length < minWordLength {
+				minWordLength = length
+			}
+			if length > maxWordLength {
+				maxWordLength = length
+			}
+			wordChars.WriteString(form)
+			usable = true
+		}
+	}
+	if !usable {
+		return ErrPolicyForbidsAlphabet
+	}
+
+	// Gather the alphabets every passphrase draws characters from. A fixed separator is written
+	// between every pair of words, so none of its characters may be forbidden.
+	var alphabets []string
+	if wordChars.Len() > 0 {
+		alphabets = append(alphabets, wordChars.String())
+	}
+	if o.WordCount > 1 && o.RandomSeparators != "" {
+		alphabets = append(alphabets, o.RandomSeparators)
+	} else if o.WordCount > 1 && o.Separator != "" {
+		if strings.ContainsAny(o.Separator, o.Policy.ForbiddenCharacters) {
+			return ErrPolicyForbidsAlphabet
+		}
+		alphabets = append(alphabets, o.Separator)
+	}
+	if o.Digits > 0 {
+		alphabets = append(alphabets, AlphabetNumeric)
+	}
+	if o.Symbols > 0 {
+		alphabets = append(alphabets, AlphabetSpecial)
+	}
+
+	err := o.Policy.validateCharacters(alphabets...)
+	if err != nil {
+		return err
+	}
+
+	// Determine the range of passphrase lengths, which never exceed the maximum length.
+	otherLength := (o.WordCount-1)*o.separatorLength() + o.Digits + o.Symbols
+	minLength := o.WordCount*minWordLength + otherLength
+	maxLength := o.WordCount*maxWordLength + otherLength
+	if o.MaxLength > 0 && o.MaxLength < maxLength {
+		maxLength = o.MaxLength
+	}
+
+	err = o.Policy.validateLength(minLength, maxLength)
+	if err != nil {
+		return err
+	}
+	return o.Policy.validateRequirements(maxLength, alphabets...)
+}
+
+// validWordLengths reports whether the minimum word length, if any, does not exceed the maximum.
+func (o PassphraseOptions) validWordLengths() bool {
+	return o.MinWordLength == 0 || o.MaxWordLength == 0 || o.MinWordLength <= o.MaxWordLength
+}
+
+// WordListSize returns the number of unique words in the word list after casing is applied and
+// words of disallowed lengths or containing injected characters or random separators are removed.
+// Words which collapse together once cased, such as "Straße" and "STRASSE" in uppercase, count once.
+func (o PassphraseOptions) WordListSize() uint {
+	return uint(len(o.uniqueWords()))
+}
+
+// uniqueWords deduplicates the word list after casing is applied with the case mappings of the
+// language, preserving the order in which words first appear. Words with fewer or more characters
+// than allowed are left out, as are words containing the digits or symbols being injected or the
+// random separators, which could otherwise be mistaken for one another and make different
+// passphrases look the same.
+func (o PassphraseOptions) uniqueWords() []string {
+	caser := newWordCaser(o.Casing, o.Language)
 	words := map[string]struct{}{}
-	for _, word := range wordList {
-		switch casing {
-		case PassphraseCasingLower:
-			words[strings.ToLower(word)] = struct{}{}
-		case PassphraseCasingUpper:
-			words[strings.ToUpper(word)] = struct{}{}
-		case PassphraseCasingTitle:
-			words[strings.Title(word)] = struct{}{}
-		case PassphraseCasingNone:
+	var wordSet []string
+	for _, word := range o.WordList {
+		for _, word := range caser.forms(word) {
+			// Leave out words of disallowed lengths.
+			length := uint(utf8.RuneCountInString(word))
+			if (o.MinWordLength > 0 && length < o.MinWordLength) || (o.MaxWordLength > 0 && length > o.MaxWordLength) {
+				continue
+			}
+
+			// Leave out words which contain injected characters or random separators.
+			if (o.Digits > 0 && strings.ContainsAny(word, AlphabetNumeric)) ||
+				(o.Symbols > 0 && strings.ContainsAny(word, AlphabetSpecial)) ||
+				strings.ContainsAny(word, o.RandomSeparators) {
+				continue
+			}
+
+			if _, ok := words[word]; ok {
+				continue
+			}
 			words[word] = struct{}{}
+			wordSet = append(wordSet, word)
 		}
 	}
-	var wordSet []string
-	for word := range words {
-		wordSet = append(wordSet, word)
+
+	return wordSet
+}
+
+// GeneratePassphrases generates random passphrases based on the configuration provided by the user,
+// using the default Generator backed by crypto/rand.
+func GeneratePassphrases(
+	count uint, // Number of passphrases to generate.
+	wordCount uint, // Length, in words, of each generated passphrase.
+	separator string, // Passphrase word separator.
+	casing PassphraseCasing, // Passphrase word casing.
+	wordList []string, // List of words to pull passphrase words from.
+) (
+	passphrases []string, // Generated passphrases.
+	err error, // Possible error encountered during passphrase generation.
+) {
+	return defaultGenerator.Passphrases(count, wordCount, separator, casing, wordList)
+}
+
+// GeneratePassphrasesWithOptions generates random passphrases based on the provided options, using
+// the default Generator backed by crypto/rand.
+func GeneratePassphrasesWithOptions(opts PassphraseOptions) ([]string, error) {
+	return defaultGenerator.PassphrasesWithOptions(opts)
+}
+
+// Passphrases generates random passphrases based on the configuration provided by the user.
+func (g *Generator) Passphrases(
+	count uint, // Number of passphrases to generate.
+	wordCount uint, // Length, in words, of each generated passphrase.
+	separator string, // Passphrase word separator.
+	casing PassphraseCasing, // Passphrase word casing.
+	wordList []string, // List of words to pull passphrase words from.
+) (
+	passphrases []string, // Generated passphrases.
+	err error, // Possible error encountered during passphrase generation.
+) {
+	return g.PassphrasesWithOptions(PassphraseOptions{
+		Count:     count,
+		WordCount: wordCount,
+		Separator: separator,
+		Casing:    casing,
+		WordList:  wordList,
+	})
+}
+
+// PassphrasesWithOptions generates random passphrases based on the provided options.
+//
+// Passphrases which exceed the maximum length are discarded and generated anew rather than
+// truncated, as are passphrases which do not comply with the policy, so the output is uniformly
+// distributed over every compliant passphrase which fits.
+// Digits and symbols are injected at the start of the passphrase or attached to the end of words,
+// with every placement equally likely. Random separators are drawn independently of one another, as
+// are the casings of words under PassphraseCasingRandomWord.
+func (g *Generator) PassphrasesWithOptions(opts PassphraseOptions) (passphrases []string, err error) {
+	// Choose the word count if an entropy target was provided.
+	opts, err = opts.Resolve()
+	if err != nil {
+		return nil, err
 	}
 
-	// Validate the provided word list.
-	if len(wordSet) < WordListLengthMin {
-		return nil, fmt.Errorf("word list must contain at least %d unique words", WordListLengthMin)
+	// Validate the options and retrieve the deduplicated word set.
+	wordSet, err := opts.wordSet()
+	if err != nil {
+		return nil, err
 	}
 
 	// Determine how many bytes are needed to represent a passphrase of the specified word count in
-	// the provided word list.
-	bitsPerWord := uint(math.Ceil(math.Log2(float64(len(wordSet)))))
-	bitsPerPassphrase := bitsPerWord * wordCount
+	// the provided word list. Rejected word indices are replaced by reading further random data.
+	bitsPerWord := uint(bits.Len(uint(len(wordSet) - 1)))
+	bitsPerPassphrase := bitsPerWord * opts.WordCount
 	bytesPerPassphrase := bitsPerPassphrase / 8
 	if bitsPerPassphrase%8 > 0 {
 		bytesPerPassphrase++
 	}
 
 	var (
-		i                uint            // Passphrase counter.
-		b                strings.Builder // String builder for efficiently constructing passphrases.
-		passphraseBuffer []byte          // Byte buffer for random data used as a passphrase source.
+		i        uint                                         // Passphrase counter.
+		attempts uint                                         // Generation attempts for the current passphrase.
+		wordIdx  uint                                         // Word index within the provided word list.
+		words    = make([]string, opts.WordCount)             // Words chosen for the current passphrase.
+		source   = newBitReader(g.source, bytesPerPassphrase) // Bit reader for random data used as passphrase source.
+
+		randomSeparators = uniqueChars(opts.RandomSeparators)       // Characters random separators are drawn from.
+		caser            = newWordCaser(opts.Casing, opts.Language) // Casing applied to each word for its position.
 	)
 
-	for i = 0; i < count; i++ {
-		// Read enough random data to sufficiently produce a passphrase.
-		passphraseBuffer = make([]byte, bytesPerPassphrase)
-		_, err = io.ReadFull(randSource, passphraseBuffer)
-		if err != nil {
-			return nil, err
-		}
+	for i = 0; i < opts.Count; i++ {
+		for attempts = 0; ; attempts++ {
+			// Give up on a maximum length or policy which is too unlikely to be met.
+			if attempts == GenerationAttemptsMax {
+				return nil, ErrRequirementsUnsatisfied
+			}
 
-		var (
-			j          uint // Passphrase word counter.
-			wordIdx    uint // Word index within the provided word list.
-			bitIdx     uint // Source buffer bit counter.
-			byteIdx    uint // Source buffer byte counter.
-			wordBitIdx uint // Passphrase word bit counter.
-		)
+			var (
+				j      uint                                                                     // Passphrase word counter.
+				length = (opts.WordCount-1)*opts.separatorLength() + opts.Digits + opts.Symbols // Length of the passphrase in characters.
+			)
 
-		for j = 0; j < wordCount; j++ {
-			for wordBitIdx = 0; wordBitIdx < bitsPerWord; wordBitIdx++ {
-				// Left shift the word index to read the next bit.
-				wordIdx <<= 1
+			for j = 0; j < opts.WordCount; j++ {
+				// Select a uniformly distributed word index within the bounds of the word set.
+				wordIdx, err = source.readIndex(uint(len(wordSet)))
+				if err != nil {
+					return nil, err
+				}
 
-				// Set the bit from the passphrase source in the word index.
-				wordIdx |= uint((passphraseBuffer[byteIdx] & (0x80 >> (bitIdx % 8))) >> (7 - (bitIdx % 8)))
+				// Retrieve the word from the word set.
+				words[j] = wordSet[wordIdx]
+				length += uint(utf8.RuneCountInString(words[j]))
+			}
 
-				// Increment the bit counter and, if necessary, the byte counter.
-				bitIdx++
-				if bitIdx%8 == 0 {
-					byteIdx++
-				}
+			// Discard the words if the passphrase would exceed the maximum length.
+			if opts.MaxLength > 0 && length > opts.MaxLength {
+				continue
 			}
 
-			// Ensure the word index is within the bounds of the word set.
-			wordIdx = wordIdx % uint(len(wordSet))
+			// Build the passphrase from the words.
+			passphrase, err := opts.assemble(words, source, randomSeparators, caser)
+			if err != nil {
+				return nil, err
+			}
+
+			// Discard the passphrase if it does not comply with the policy, otherwise append it to
+			// the return list.
+			if opts.Policy.complies(passphrase) {
+				passphrases = append(passphrases, passphrase)
+				break
+			}
+		}
+	}
+
+	return
+}
+
+// assemble builds a passphrase from the chosen words, drawing the word to capitalize, the digits
+// and symbols to inject, and the random separators from the source as the options require.
+func (o PassphraseOptions) assemble(
+	words []string, // Words chosen for the passphrase, in order.
+	source *bitReader, // Bit reader for random data used as passphrase source.
+	randomSeparators []rune, // Characters random separators are drawn from, if any.
+	caser *wordCaser, // Casing applied to each word for its position.
+) (string, error) {
+	// Choose the word to capitalize if a single word is capitalized.
+	var (
+		capital uint
+		err     error
+	)
+	if o.Casing == PassphraseCasingOneCapital {
+		capital, err = source.readIndex(o.WordCount)
+		if err != nil {
+			return "", err
+		}
+	}
 
-			// Retrieve the word from the word set and write it to the passphrase.
-			b.WriteString(wordSet[wordIdx])
+	// Choose the digits and symbols to inject, which never change the length of the passphrase.
+	injected, err := o.injections(source)
+	if err != nil {
+		return "", err
+	}
 
-			// Write the provided separator if this is not the final word in the passphrase.
-			if j < wordCount-1 {
-				b.WriteRune(separator)
+	// Write the characters injected at the start of the passphrase, then each word cased for its
+	// position followed by the characters attached to it, separating the words with the provided
+	// separator or one drawn at random.
+	var b strings.Builder
+	b.WriteString(injected[0])
+	for j, word := range words {
+		if j > 0 && len(randomSeparators) > 0 {
+			sepIdx, err := source.readIndex(uint(len(randomSeparators)))
+			if err != nil {
+				return "", err
 			}
+			b.WriteRune(randomSeparators[sepIdx])
+		} else if j > 0 {
+			b.WriteString(o.Separator)
+		}
+		b.WriteString(caser.caseWord(word, uint(j), capital))
+		b.WriteString(injected[j+1])
+	}
+
+	return b.String(), nil
+}
+
+// injections chooses the digits and symbols to inject into a passphrase, returning the characters
+// placed at the start of the passphrase followed by those attached to the end of each word.
+//
+// Every arrangement of the words, digits, and symbols which keeps the words in order is equally
+// likely: each position of the arrangement holds an injected character with probability
+// proportional to the characters left to place, which is a digit with probability proportional to
+// the digits left to place.
+func (o PassphraseOptions) injections(source *bitReader) ([]string, error) {
+	var (
+		injected  = make([][]byte, o.WordCount+1)      // Characters injected at each position.
+		position  uint                                 // Index of the current injection position.
+		digits    = o.Digits                           // Digits left to place.
+		remaining = o.Digits + o.Symbols               // Characters left to place.
+		tokens    = o.WordCount + o.Digits + o.Symbols // Words and characters left to arrange.
+	)
 
-			// Reset the word index value.
-			wordIdx = 0
+	for ; remaining > 0; tokens-- {
+		// Determine whether the next token is a word or an injected character.
+		idx, err := source.readIndex(tokens)
+		if err != nil {
+			return nil, err
+		}
+		if idx >= remaining {
+			position++
+			continue
 		}
 
-		// Append the passphrase to the return list.
-		passphrases = append(passphrases, b.String())
+		// Determine whether the injected character is a digit or a symbol.
+		alphabet := AlphabetSpecial
+		idx, err = source.readIndex(remaining)
+		if err != nil {
+			return nil, err
+		}
+		if idx < digits {
+			alphabet = AlphabetNumeric
+			digits--
+		}
 
-		// Reset the string builder for the next passphrase.
-		b.Reset()
+		// Select a uniformly distributed character from the alphabet.
+		idx, err = source.readIndex(uint(len(alphabet)))
+		if err != nil {
+			return nil, err
+		}
+		injected[position] = append(injected[position], alphabet[idx])
+		remaining--
 	}
 
-	return
+	chars := make([]string, len(injected))
+	for j := range injected {
+		chars[j] = string(injected[j])
+	}
+
+	return chars, nil
 }
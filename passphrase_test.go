@@ -2,11 +2,16 @@ package passgen
 
 import (
 	"bytes"
-	"io"
+	"errors"
+	"math"
+	"math/rand"
+	"strconv"
 	"strings"
 	"testing"
+	"unicode"
 
 	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
 )
 
 func TestGeneratePassphrases(t *testing.T) {
@@ -16,7 +21,7 @@ func TestGeneratePassphrases(t *testing.T) {
 		name      string
 		count     uint
 		wordCount uint
-		separator rune
+		separator string
 		casing    PassphraseCasing
 		wordList  []string
 
@@ -42,7 +47,7 @@ func TestGeneratePassphrases(t *testing.T) {
 				require.NoError(t, err)
 				require.Len(t, passphrases, PassphraseCountDefault)
 				for _, passphrase := range passphrases {
-					words := strings.Split(passphrase, string(PassphraseSeparatorDefault))
+					words := strings.Split(passphrase, PassphraseSeparatorDefault)
 					require.Len(t, words, PassphraseWordCountDefault)
 					for _, word := range words {
 						require.Contains(t, WordListDefault, word)
@@ -65,7 +70,7 @@ func TestGeneratePassphrases(t *testing.T) {
 				require.NoError(t, err)
 				require.Len(t, passphrases, PassphraseCountDefault+1)
 				for _, passphrase := range passphrases {
-					words := strings.Split(passphrase, string(PassphraseSeparatorDash))
+					words := strings.Split(passphrase, PassphraseSeparatorDash)
 					require.Len(t, words, PassphraseWordCountDefault+1)
 					for _, word := range words {
 						require.Contains(t, alternateWordList, strings.ToLower(word))
@@ -88,7 +93,7 @@ func TestGeneratePassphrases(t *testing.T) {
 				require.NoError(t, err)
 				require.Len(t, passphrases, PassphraseCountDefault+1)
 				for _, passphrase := range passphrases {
-					words := strings.Split(passphrase, string(PassphraseSeparatorUnderscore))
+					words := strings.Split(passphrase, PassphraseSeparatorUnderscore)
 					require.Len(t, words, PassphraseWordCountDefault+1)
 					for _, word := range words {
 						require.Contains(t, alternateWordList, strings.ToLower(word))
@@ -111,7 +116,7 @@ func TestGeneratePassphrases(t *testing.T) {
 				require.NoError(t, err)
 				require.Len(t, passphrases, PassphraseCountDefault+1)
 				for _, passphrase := range passphrases {
-					words := strings.Split(passphrase, string(PassphraseSeparatorDot))
+					words := strings.Split(passphrase, PassphraseSeparatorDot)
 					require.Len(t, words, PassphraseWordCountDefault+1)
 					for _, word := range words {
 						require.Contains(t, alternateWordList, strings.ToLower(word))
@@ -122,6 +127,66 @@ func TestGeneratePassphrases(t *testing.T) {
 			nil,
 			nil,
 		},
+		{
+			"multi-character separator",
+			PassphraseCountDefault + 1,
+			PassphraseWordCountDefault,
+			" - ",
+			PassphraseCasingNone,
+			alternateWordList,
+
+			func(t *testing.T, passphrases []string, err error) {
+				require.NoError(t, err)
+				require.Len(t, passphrases, PassphraseCountDefault+1)
+				for _, passphrase := range passphrases {
+					words := strings.Split(passphrase, " - ")
+					require.Len(t, words, PassphraseWordCountDefault)
+					for _, word := range words {
+						require.Contains(t, alternateWordList, word)
+					}
+				}
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"colon separator",
+			PassphraseCountDefault,
+			PassphraseWordCountDefault,
+			PassphraseSeparatorColon,
+			PassphraseCasingNone,
+			alternateWordList,
+
+			func(t *testing.T, passphrases []string, err error) {
+				require.NoError(t, err)
+				require.Equal(t, ":", PassphraseSeparatorColon)
+				for _, passphrase := range passphrases {
+					require.Len(t, strings.Split(passphrase, ":"), PassphraseWordCountDefault)
+				}
+			},
+
+			nil,
+			nil,
+		},
+		{
+			"empty separator",
+			PassphraseCountDefault,
+			PassphraseWordCountDefault,
+			PassphraseSeparatorNone,
+			PassphraseCasingNone,
+			[]string{"alfa", "bravo"},
+
+			func(t *testing.T, passphrases []string, err error) {
+				require.NoError(t, err)
+				for _, passphrase := range passphrases {
+					require.Regexp(t, "^(alfa|bravo){6}$", passphrase)
+				}
+			},
+
+			nil,
+			nil,
+		},
 		{
 			"count too small",
 			PassphraseCountMin - 1,
@@ -248,12 +313,12 @@ func TestGeneratePassphrases(t *testing.T) {
 			},
 
 			func() interface{} {
-				originalRandSource := randSource
-				randSource = new(bytes.Reader)
-				return originalRandSource
+				originalGenerator := defaultGenerator
+				defaultGenerator = NewGenerator(new(bytes.Reader))
+				return originalGenerator
 			},
 			func(setupContext interface{}) {
-				randSource = setupContext.(io.Reader)
+				defaultGenerator = setupContext.(*Generator)
 			},
 		},
 	}
@@ -284,12 +349,1063 @@ func TestGeneratePassphrases(t *testing.T) {
 	}
 }
 
+func TestPassphraseOptions(t *testing.T) {
+	type testDef struct {
+		name    string
+		options PassphraseOptions
+
+		requirements func(t *testing.T, err error)
+	}
+
+	var tests = []testDef{
+		{
+			"rational defaults",
+			DefaultPassphraseOptions(),
+
+			func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			"count out of range",
+			PassphraseOptions{
+				Count:     PassphraseCountMax + 1,
+				WordCount: PassphraseWordCountDefault,
+				Separator: PassphraseSeparatorDefault,
+				Casing:    PassphraseCasingDefault,
+				WordList:  WordListDefault,
+			},
+
+			func(t *testing.T, err error) {
+				var rangeErr *RangeError
+				require.True(t, errors.As(err, &rangeErr))
+				require.Equal(t, "count", rangeErr.Parameter)
+				require.EqualValues(t, PassphraseCountMin, rangeErr.Min)
+				require.EqualValues(t, PassphraseCountMax, rangeErr.Max)
+			},
+		},
+		{
+			"word count out of range",
+			PassphraseOptions{
+				Count:     PassphraseCountDefault,
+				WordCount: PassphraseWordCountMin - 1,
+				Separator: PassphraseSeparatorDefault,
+				Casing:    PassphraseCasingDefault,
+				WordList:  WordListDefault,
+			},
+
+			func(t *testing.T, err error) {
+				var rangeErr *RangeError
+				require.True(t, errors.As(err, &rangeErr))
+				require.Equal(t, "word count", rangeErr.Parameter)
+			},
+		},
+		{
+			"invalid casing",
+			PassphraseOptions{
+				Count:     PassphraseCountDefault,
+				WordCount: PassphraseWordCountDefault,
+				Separator: PassphraseSeparatorDefault,
+				Casing:    PassphraseCasing(^uint8(0)),
+				WordList:  WordListDefault,
+			},
+
+			func(t *testing.T, err error) {
+				require.True(t, errors.Is(err, ErrInvalidCasing))
+			},
+		},
+		{
+			"word list collapses under casing",
+			PassphraseOptions{
+				Count:     PassphraseCountDefault,
+				WordCount: PassphraseWordCountDefault,
+				Separator: PassphraseSeparatorDefault,
+				Casing:    PassphraseCasingLower,
+				WordList:  []string{"alfa", "ALFA", "Alfa"},
+			},
+
+			func(t *testing.T, err error) {
+				require.True(t, errors.Is(err, ErrWordListTooSmall))
+			},
+		},
+		{
+			"minimum word length above maximum",
+			PassphraseOptions{
+				Count:         PassphraseCountDefault,
+				WordCount:     PassphraseWordCountDefault,
+				Separator:     PassphraseSeparatorDefault,
+				Casing:        PassphraseCasingDefault,
+				WordList:      WordListDefault,
+				MinWordLength: 6,
+				MaxWordLength: 3,
+			},
+
+			func(t *testing.T, err error) {
+				require.True(t, errors.Is(err, ErrInvalidWordLength))
+			},
+		},
+		{
+			"word list filtered by length",
+			PassphraseOptions{
+				Count:         PassphraseCountDefault,
+				WordCount:     PassphraseWordCountDefault,
+				Separator:     PassphraseSeparatorDefault,
+				Casing:        PassphraseCasingDefault,
+				WordList:      []string{"alfa", "bravo", "charlie"},
+				MinWordLength: 5,
+				MaxWordLength: 6,
+			},
+
+			func(t *testing.T, err error) {
+				require.True(t, errors.Is(err, ErrWordListTooSmall))
+			},
+		},
+		{
+			"entropy target with minimum word length above maximum",
+			PassphraseOptions{
+				Count:         PassphraseCountDefault,
+				WordCount:     PassphraseWordCountDefault,
+				Separator:     PassphraseSeparatorDefault,
+				Casing:        PassphraseCasingDefault,
+				WordList:      WordListDefault,
+				MinWordLength: 6,
+				MaxWordLength: 3,
+				MinEntropy:    64,
+			},
+
+			func(t *testing.T, err error) {
+				require.True(t, errors.Is(err, ErrInvalidWordLength))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name,
+			func(t *testing.T) {
+				test.requirements(t, test.options.Validate())
+
+				passphrases, err := GeneratePassphrasesWithOptions(test.options)
+				test.requirements(t, err)
+				if err == nil {
+					require.Len(t, passphrases, int(test.options.Count))
+				} else {
+					require.Empty(t, passphrases)
+				}
+			},
+		)
+	}
+}
+
+func TestPassphraseCasingValues(t *testing.T) {
+	// Casings keep their original values, and constants added elsewhere never renumber them.
+	require.EqualValues(t, 28, PassphraseCasingLower)
+	require.EqualValues(t, 29, PassphraseCasingUpper)
+	require.EqualValues(t, 30, PassphraseCasingTitle)
+	require.EqualValues(t, 31, PassphraseCasingNone)
+	require.EqualValues(t, 32, PassphraseCasingRandomWord)
+	require.EqualValues(t, 33, PassphraseCasingCamelCase)
+	require.EqualValues(t, 34, PassphraseCasingAlternating)
+	require.EqualValues(t, 35, PassphraseCasingOneCapital)
+	require.Equal(t, PassphraseCasingNone, PassphraseCasingDefault)
+}
+
+func TestPassphraseOptionsEntropy(t *testing.T) {
+	entropy, err := DefaultPassphraseOptions().Entropy()
+	require.NoError(t, err)
+	require.InDelta(t, PassphraseWordCountDefault*math.Log2(7776), entropy, 1e-9)
+
+	// Words which collapse together under casing only count once.
+	opts := DefaultPassphraseOptions()
+	opts.Casing = PassphraseCasingLower
+	opts.WordList = []string{"alfa", "ALFA", "bravo", "charlie", "delta"}
+	entropy, err = opts.Entropy()
+	require.NoError(t, err)
+	require.InDelta(t, PassphraseWordCountDefault*2.0, entropy, 1e-9)
+
+	// Only words of the allowed lengths count.
+	opts = DefaultPassphraseOptions()
+	opts.WordList = []string{"a", "bb", "ccc", "dddd", "éééé", "fffff", "gggggg", "hhhhhhh"}
+	opts.MinWordLength = 3
+	opts.MaxWordLength = 6
+	entropy, err = opts.Entropy()
+	require.NoError(t, err)
+	require.EqualValues(t, 5, opts.WordListSize())
+	require.InDelta(t, PassphraseWordCountDefault*math.Log2(5), entropy, 1e-9)
+
+	_, err = PassphraseOptions{}.Entropy()
+	require.Error(t, err)
+}
+
+func TestGeneratePassphrasesWordLength(t *testing.T) {
+	type testDef struct {
+		name          string
+		minWordLength uint
+		maxWordLength uint
+	}
+
+	var tests = []testDef{
+		{"minimum", 7, 0},
+		{"maximum", 0, 4},
+		{"range", 3, 6},
+		{"exact", 5, 5},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name,
+			func(t *testing.T) {
+				opts := DefaultPassphraseOptions()
+				opts.Count = 16
+				opts.MinWordLength = test.minWordLength
+				opts.MaxWordLength = test.maxWordLength
+
+				// Ensure the word set only holds words of the allowed lengths.
+				wordSet, err := opts.wordSet()
+				require.NoError(t, err)
+				require.Less(t, len(wordSet), len(WordListDefault))
+				require.EqualValues(t, len(wordSet), opts.WordListSize())
+
+				entropy, err := opts.Entropy()
+				require.NoError(t, err)
+				require.InDelta(t, float64(opts.WordCount)*math.Log2(float64(len(wordSet))), entropy, 1e-9)
+
+				passphrases, err := GeneratePassphrasesWithOptions(opts)
+				require.NoError(t, err)
+				for _, passphrase := range passphrases {
+					words := strings.Split(passphrase, opts.Separator)
+					require.Len(t, words, int(opts.WordCount))
+					for _, word := range words {
+						require.Contains(t, wordSet, word)
+						if test.minWordLength > 0 {
+							require.GreaterOrEqual(t, len(word), int(test.minWordLength))
+						}
+						if test.maxWordLength > 0 {
+							require.LessOrEqual(t, len(word), int(test.maxWordLength))
+						}
+					}
+				}
+			},
+		)
+	}
+}
+
+func TestPassphraseOptionsResolve(t *testing.T) {
+	opts := DefaultPassphraseOptions()
+	resolved, err := opts.Resolve()
+	require.NoError(t, err)
+	require.Equal(t, opts, resolved)
+
+	// The default word list provides just under 13 bits of entropy per word.
+	opts.MinEntropy = 128
+	resolved, err = opts.Resolve()
+	require.NoError(t, err)
+	require.EqualValues(t, 10, resolved.WordCount)
+
+	opts.MinEntropy = 1
+	resolved, err = opts.Resolve()
+	require.NoError(t, err)
+	require.EqualValues(t, PassphraseWordCountMin, resolved.WordCount)
+
+	opts.MinEntropy = 10000
+	_, err = opts.Resolve()
+	require.True(t, errors.Is(err, ErrEntropyUnreachable))
+
+	opts.MinEntropy = 128
+	opts.WordList = []string{"x"}
+	_, err = opts.Resolve()
+	require.True(t, errors.Is(err, ErrWordListTooSmall))
+}
+
+func TestGeneratePassphrasesDistribution(t *testing.T) {
+	// Use a seeded pseudorandom source so the test is reproducible.
+	generator := NewGenerator(rand.New(rand.NewSource(1)))
+
+	// A five word list is not a power of two in size, so reducing random indices modulo its size
+	// would select the first three words twice as often as the others.
+	wordList := []string{"alfa", "bravo", "charlie", "delta", "echo"}
+
+	passphrases, err := generator.Passphrases(
+		PassphraseCountMax,
+		PassphraseWordCountMax,
+		PassphraseSeparatorDefault,
+		PassphraseCasingDefault,
+		wordList,
+	)
+	require.NoError(t, err)
+
+	// Tally how often each word of the list was selected.
+	counts := map[string]int{}
+	for _, passphrase := range passphrases {
+		for _, word := range strings.Split(passphrase, PassphraseSeparatorDefault) {
+			counts[word]++
+		}
+	}
+
+	// Words must be selected uniformly.
+	require.Len(t, counts, len(wordList))
+	require.Less(t, chiSquared(counts, len(wordList)), chiSquaredCritical(len(wordList)-1))
+}
+
+func TestGeneratePassphrasesMaxLength(t *testing.T) {
+	t.Run(
+		"default word list",
+		func(t *testing.T) {
+			opts := DefaultPassphraseOptions()
+			opts.Count = 16
+			opts.MaxLength = 32
+
+			passphrases, err := GeneratePassphrasesWithOptions(opts)
+			require.NoError(t, err)
+			require.Len(t, passphrases, 16)
+			for _, passphrase := range passphrases {
+				require.LessOrEqual(t, len(passphrase), 32)
+				require.Len(t, strings.Split(passphrase, opts.Separator), PassphraseWordCountDefault)
+			}
+
+			// Discarding the majority of passphrases for being too long reduces the entropy.
+			entropy, err := opts.Entropy()
+			require.NoError(t, err)
+			require.Less(t, entropy, PassphraseWordCountDefault*math.Log2(7776)-8)
+			require.Greater(t, entropy, 60.0)
+		},
+	)
+
+	t.Run(
+		"uniform over passphrases which fit",
+		func(t *testing.T) {
+			// Use a seeded pseudorandom source so the test is reproducible.
+			generator := NewGenerator(rand.New(rand.NewSource(1)))
+
+			// Three words of lengths 1 to 3 total at most 5 characters in 10 of 27 passphrases.
+			opts := PassphraseOptions{
+				Count:     PassphraseCountMax,
+				WordCount: 3,
+				Separator: PassphraseSeparatorDash,
+				Casing:    PassphraseCasingNone,
+				WordList:  []string{"a", "bb", "ccc"},
+				MaxLength: 7,
+			}
+
+			entropy, err := opts.Entropy()
+			require.NoError(t, err)
+			require.InDelta(t, math.Log2(10), entropy, 1e-9)
+
+			passphrases, err := generator.PassphrasesWithOptions(opts)
+			require.NoError(t, err)
+
+			// Tally how often each passphrase was generated.
+			counts := map[string]int{}
+			for _, passphrase := range passphrases {
+				require.LessOrEqual(t, len(passphrase), 7)
+				counts[passphrase]++
+			}
+
+			// Passphrases must be selected uniformly.
+			require.Len(t, counts, 10)
+			require.Less(t, chiSquared(counts, 10), chiSquaredCritical(10-1))
+		},
+	)
+
+	t.Run(
+		"entropy target",
+		func(t *testing.T) {
+			// A maximum length lowers the entropy of each word, so more words are needed.
+			opts := DefaultPassphraseOptions()
+			opts.MinEntropy = 64
+			resolved, err := opts.Resolve()
+			require.NoError(t, err)
+			require.EqualValues(t, 5, resolved.WordCount)
+
+			opts.MaxLength = 36
+			resolved, err = opts.Resolve()
+			require.NoError(t, err)
+			require.EqualValues(t, 6, resolved.WordCount)
+
+			entropy, err := resolved.Entropy()
+			require.NoError(t, err)
+			require.GreaterOrEqual(t, entropy, 64.0)
+
+			// No number of words meets the target within a short maximum length.
+			opts.MaxLength = 30
+			_, err = opts.Resolve()
+			require.True(t, errors.Is(err, ErrEntropyUnreachable))
+		},
+	)
+
+	t.Run(
+		"unsatisfiable",
+		func(t *testing.T) {
+			// Six words and their separators never fit within 10 characters.
+			opts := DefaultPassphraseOptions()
+			opts.MaxLength = 10
+			require.True(t, errors.Is(opts.Validate(), ErrRequirementsUnsatisfied))
+
+			passphrases, err := GeneratePassphrasesWithOptions(opts)
+			require.True(t, errors.Is(err, ErrRequirementsUnsatisfied))
+			require.Empty(t, passphrases)
+
+			// The separators alone exceed the maximum length.
+			opts.MaxLength = 4
+			opts.WordCount = PassphraseWordCountMax
+			_, err = opts.Entropy()
+			require.True(t, errors.Is(err, ErrRequirementsUnsatisfied))
+		},
+	)
+}
+
+func TestGeneratePassphrasesInjection(t *testing.T) {
+	t.Run(
+		"digits and symbols",
+		func(t *testing.T) {
+			opts := DefaultPassphraseOptions()
+			opts.Count = 64
+			opts.Digits = 2
+			opts.Symbols = 3
+
+			passphrases, err := GeneratePassphrasesWithOptions(opts)
+			require.NoError(t, err)
+			require.Len(t, passphrases, 64)
+			for _, passphrase := range passphrases {
+				var digits, symbols int
+				for _, char := range passphrase {
+					switch {
+					case strings.ContainsRune(AlphabetNumeric, char):
+						digits++
+					case strings.ContainsRune(AlphabetSpecial, char):
+						symbols++
+					}
+				}
+				require.Equal(t, 2, digits)
+				require.Equal(t, 3, symbols)
+
+				// Stripping the injected characters leaves words from the list.
+				stripped := strings.Map(func(char rune) rune {
+					if strings.ContainsRune(AlphabetNumeric+AlphabetSpecial, char) {
+						return -1
+					}
+					return char
+				}, passphrase)
+				words := strings.Split(stripped, opts.Separator)
+				require.Len(t, words, PassphraseWordCountDefault)
+				for _, word := range words {
+					require.Contains(t, WordListDefault, word)
+				}
+			}
+		},
+	)
+
+	t.Run(
+		"entropy counts distinct passphrases",
+		func(t *testing.T) {
+			// The dash separator is itself a symbol, yet every arrangement must remain distinct.
+			opts := PassphraseOptions{
+				Count:     PassphraseCountDefault,
+				WordCount: 3,
+				Separator: PassphraseSeparatorDash,
+				Casing:    PassphraseCasingNone,
+				WordList:  []string{"a", "b"},
+				Digits:    1,
+				Symbols:   1,
+			}
+
+			// Enumerate every arrangement of the three words, the digit, and the symbol.
+			distinct := map[string]struct{}{}
+			var arrange func(tokens []byte, words, digits, symbols int)
+			arrange = func(tokens []byte, words, digits, symbols int) {
+				if words == 0 && digits == 0 && symbols == 0 {
+					for _, wordSeq := range []string{"aaa", "aab", "aba", "abb", "baa", "bab", "bba", "bbb"} {
+						for _, digit := range AlphabetNumeric {
+							for _, symbol := range AlphabetSpecial {
+								var b strings.Builder
+								wordIdx := 0
+								for _, token := range tokens {
+									switch token {
+									case 'w':
+										if wordIdx > 0 {
+											b.WriteString(opts.Separator)
+										}
+										b.WriteByte(wordSeq[wordIdx])
+										wordIdx++
+									case 'd':
+										b.WriteRune(digit)
+									case 's':
+										b.WriteRune(symbol)
+									}
+								}
+								distinct[b.String()] = struct{}{}
+							}
+						}
+					}
+					return
+				}
+				if words > 0 {
+					arrange(append(tokens, 'w'), words-1, digits, symbols)
+				}
+				if digits > 0 {
+					arrange(append(tokens, 'd'), words, digits-1, symbols)
+				}
+				if symbols > 0 {
+					arrange(append(tokens, 's'), words, digits, symbols-1)
+				}
+			}
+			arrange(nil, 3, 1, 1)
+
+			entropy, err := opts.Entropy()
+			require.NoError(t, err)
+			require.InDelta(t, math.Log2(float64(len(distinct))), entropy, 1e-9)
+			require.InDelta(t, 3+math.Log2(20*8*12), entropy, 1e-9)
+		},
+	)
+
+	t.Run(
+		"uniform placement",
+		func(t *testing.T) {
+			// Use a seeded pseudorandom source so the test is reproducible.
+			generator := NewGenerator(rand.New(rand.NewSource(1)))
+
+			opts := PassphraseOptions{
+				Count:     PassphraseCountMax,
+				WordCount: 4,
+				Separator: PassphraseSeparatorSpace,
+				Casing:    PassphraseCasingNone,
+				WordList:  []string{"alfa", "bravo", "charlie"},
+				Digits:    1,
+			}
+
+			passphrases, err := generator.PassphrasesWithOptions(opts)
+			require.NoError(t, err)
+
+			// Tally the position the digit was injected at, and the digit itself.
+			positions := map[string]int{}
+			digits := map[string]int{}
+			for _, passphrase := range passphrases {
+				// The digit is at the start of the passphrase or follows the word before it.
+				idx := strings.IndexAny(passphrase, AlphabetNumeric)
+				position := 0
+				if idx > 0 {
+					position = strings.Count(passphrase[:idx], " ") + 1
+				}
+				positions[strconv.Itoa(position)]++
+				digits[passphrase[idx:idx+1]]++
+			}
+
+			// Positions and digits must be selected uniformly.
+			require.Len(t, positions, 5)
+			require.Less(t, chiSquared(positions, 5), chiSquaredCritical(5-1))
+			require.Len(t, digits, len(AlphabetNumeric))
+			require.Less(t, chiSquared(digits, len(AlphabetNumeric)), chiSquaredCritical(len(AlphabetNumeric)-1))
+		},
+	)
+
+	t.Run(
+		"maximum length includes injected characters",
+		func(t *testing.T) {
+			opts := DefaultPassphraseOptions()
+			opts.Count = 16
+			opts.MaxLength = 36
+			opts.Digits = 2
+			opts.Symbols = 2
+
+			passphrases, err := GeneratePassphrasesWithOptions(opts)
+			require.NoError(t, err)
+			for _, passphrase := range passphrases {
+				require.LessOrEqual(t, len(passphrase), 36)
+			}
+
+			// The injected characters leave less room for words, as if the maximum length were
+			// shorter.
+			opts.Digits, opts.Symbols = 4, 0
+			withInjection, err := opts.Entropy()
+			require.NoError(t, err)
+			injection := opts.injectionEntropy()
+
+			opts.Digits = 0
+			opts.MaxLength = 32
+			shorter, err := opts.Entropy()
+			require.NoError(t, err)
+			require.InDelta(t, shorter+injection, withInjection, 1e-9)
+		},
+	)
+
+	t.Run(
+		"invalid options",
+		func(t *testing.T) {
+			opts := DefaultPassphraseOptions()
+			opts.Digits = PassphraseDigitsMax + 1
+			var rangeErr *RangeError
+			require.True(t, errors.As(opts.Validate(), &rangeErr))
+			require.Equal(t, "digits", rangeErr.Parameter)
+
+			opts = DefaultPassphraseOptions()
+			opts.Symbols = PassphraseSymbolsMax + 1
+			require.True(t, errors.As(opts.Validate(), &rangeErr))
+			require.Equal(t, "symbols", rangeErr.Parameter)
+
+		},
+	)
+
+	t.Run(
+		"words containing injected characters",
+		func(t *testing.T) {
+			// Words which could be confused with injected characters are left out.
+			opts := DefaultPassphraseOptions()
+			opts.WordList = []string{"alfa", "bravo", "r2d2", "x-ray"}
+			require.EqualValues(t, 4, opts.WordListSize())
+
+			opts.Digits = 1
+			require.EqualValues(t, 3, opts.WordListSize())
+			entropy, err := opts.Entropy()
+			require.NoError(t, err)
+			require.InDelta(t, PassphraseWordCountDefault*math.Log2(3)+opts.injectionEntropy(), entropy, 1e-9)
+
+			opts.Symbols = 1
+			require.EqualValues(t, 2, opts.WordListSize())
+
+			opts.WordList = []string{"alfa", "r2d2", "x-ray"}
+			require.True(t, errors.Is(opts.Validate(), ErrWordListTooSmall))
+
+			// The default list holds a few hyphenated words.
+			opts = DefaultPassphraseOptions()
+			opts.Symbols = 1
+			require.Less(t, opts.WordListSize(), uint(len(WordListDefault)))
+			require.Greater(t, opts.WordListSize(), uint(len(WordListDefault)-16))
+		},
+	)
+}
+
+func TestGeneratePassphrasesRandomSeparators(t *testing.T) {
+	t.Run(
+		"separators drawn from the set",
+		func(t *testing.T) {
+			// Use a seeded pseudorandom source so the test is reproducible.
+			generator := NewGenerator(rand.New(rand.NewSource(1)))
+
+			opts := PassphraseOptions{
+				Count:            PassphraseCountMax,
+				WordCount:        PassphraseWordCountDefault,
+				Separator:        PassphraseSeparatorDefault,
+				Casing:           PassphraseCasingNone,
+				WordList:         []string{"alfa", "bravo", "charlie", "delta", "echo"},
+				RandomSeparators: "-+=~",
+			}
+
+			passphrases, err := generator.PassphrasesWithOptions(opts)
+			require.NoError(t, err)
+
+			// Tally how often each separator was drawn.
+			counts := map[string]int{}
+			for _, passphrase := range passphrases {
+				require.NotContains(t, passphrase, " ")
+				words := strings.FieldsFunc(passphrase, func(char rune) bool {
+					if strings.ContainsRune(opts.RandomSeparators, char) {
+						counts[string(char)]++
+						return true
+					}
+					return false
+				})
+				require.Len(t, words, PassphraseWordCountDefault)
+				for _, word := range words {
+					require.Contains(t, opts.WordList, word)
+				}
+			}
+
+			// Separators must be selected uniformly.
+			require.Len(t, counts, 4)
+			require.Less(t, chiSquared(counts, 4), chiSquaredCritical(4-1))
+
+			// Each separator contributes two bits of entropy.
+			entropy, err := opts.Entropy()
+			require.NoError(t, err)
+			require.InDelta(t, PassphraseWordCountDefault*math.Log2(5)+(PassphraseWordCountDefault-1)*2, entropy, 1e-9)
+		},
+	)
+
+	t.Run(
+		"default set",
+		func(t *testing.T) {
+			opts := DefaultPassphraseOptions()
+			opts.RandomSeparators = PassphraseRandomSeparatorsDefault
+
+			// Hyphenated words could be mistaken for two words joined by a random separator.
+			require.Less(t, opts.WordListSize(), uint(len(WordListDefault)))
+
+			entropy, err := opts.Entropy()
+			require.NoError(t, err)
+			require.InDelta(
+				t,
+				PassphraseWordCountDefault*math.Log2(float64(opts.WordListSize()))+
+					(PassphraseWordCountDefault-1)*math.Log2(float64(len(PassphraseRandomSeparatorsDefault))),
+				entropy,
+				1e-9,
+			)
+
+			passphrases, err := GeneratePassphrasesWithOptions(opts)
+			require.NoError(t, err)
+			require.Len(t, passphrases, PassphraseCountDefault)
+		},
+	)
+
+	t.Run(
+		"maximum length",
+		func(t *testing.T) {
+			// Multi-character separators leave less room for words than random separators.
+			opts := DefaultPassphraseOptions()
+			opts.Count = 16
+			opts.Separator = " :: "
+			opts.MaxLength = 48
+
+			passphrases, err := GeneratePassphrasesWithOptions(opts)
+			require.NoError(t, err)
+			for _, passphrase := range passphrases {
+				require.LessOrEqual(t, len(passphrase), 48)
+				require.Len(t, strings.Split(passphrase, " :: "), PassphraseWordCountDefault)
+			}
+			separated, err := opts.Entropy()
+			require.NoError(t, err)
+
+			opts.Separator = PassphraseSeparatorNone
+			opts.MaxLength = 48 - (PassphraseWordCountDefault-1)*4
+			joined, err := opts.Entropy()
+			require.NoError(t, err)
+			require.InDelta(t, joined, separated, 1e-9)
+
+			opts.RandomSeparators = "0123"
+			opts.MaxLength = 48 - (PassphraseWordCountDefault-1)*3
+			random, err := opts.Entropy()
+			require.NoError(t, err)
+			require.InDelta(t, joined+(PassphraseWordCountDefault-1)*2, random, 1e-9)
+		},
+	)
+}
+
+func TestGeneratePassphrasesCasing(t *testing.T) {
+	wordList := []string{"alfa", "bravo", "charlie", "delta", "echo"}
+
+	t.Run(
+		"random word",
+		func(t *testing.T) {
+			// Use a seeded pseudorandom source so the test is reproducible.
+			generator := NewGenerator(rand.New(rand.NewSource(1)))
+
+			opts := PassphraseOptions{
+				Count:     PassphraseCountMax,
+				WordCount: PassphraseWordCountDefault,
+				Separator: PassphraseSeparatorDefault,
+				Casing:    PassphraseCasingRandomWord,
+				WordList:  wordList,
+			}
+
+			// Every word may be lowercase, uppercase, or title case.
+			require.Equal(t, uint(3*len(wordList)), opts.WordListSize())
+
+			passphrases, err := generator.PassphrasesWithOptions(opts)
+			require.NoError(t, err)
+
+			// Tally how often each casing was chosen.
+			counts := map[string]int{}
+			for _, passphrase := range passphrases {
+				words := strings.Split(passphrase, PassphraseSeparatorDefault)
+				require.Len(t, words, PassphraseWordCountDefault)
+				for _, word := range words {
+					require.Contains(t, wordList, strings.ToLower(word))
+					switch word {
+					case strings.ToLower(word):
+						counts["lower"]++
+					case strings.ToUpper(word):
+						counts["upper"]++
+					default:
+						require.Equal(t, strings.Title(strings.ToLower(word)), word)
+						counts["title"]++
+					}
+				}
+			}
+
+			// Casings must be selected uniformly.
+			require.Len(t, counts, 3)
+			require.Less(t, chiSquared(counts, 3), chiSquaredCritical(3-1))
+
+			// Each word contributes the choice of its casing.
+			entropy, err := opts.Entropy()
+			require.NoError(t, err)
+			require.InDelta(t, PassphraseWordCountDefault*math.Log2(float64(3*len(wordList))), entropy, 1e-9)
+		},
+	)
+
+	t.Run(
+		"random word collapses",
+		func(t *testing.T) {
+			// Casings which leave a word unchanged only count once.
+			opts := PassphraseOptions{
+				Count:     PassphraseCountDefault,
+				WordCount: PassphraseWordCountDefault,
+				Separator: PassphraseSeparatorDefault,
+				Casing:    PassphraseCasingRandomWord,
+				WordList:  []string{"a", "Bravo", "42"},
+			}
+			require.Equal(t, uint(6), opts.WordListSize())
+
+			passphrases, err := GeneratePassphrasesWithOptions(opts)
+			require.NoError(t, err)
+			require.Len(t, passphrases, PassphraseCountDefault)
+		},
+	)
+
+	t.Run(
+		"camel case",
+		func(t *testing.T) {
+			opts := PassphraseOptions{
+				Count:     PassphraseCountMax,
+				WordCount: PassphraseWordCountDefault,
+				Separator: PassphraseSeparatorNone,
+				Casing:    PassphraseCasingCamelCase,
+				WordList:  []string{"Alfa", "BRAVO", "charlie", "delta", "echo"},
+			}
+
+			passphrases, err := GeneratePassphrasesWithOptions(opts)
+			require.NoError(t, err)
+			for _, passphrase := range passphrases {
+				words := splitCapitals(passphrase)
+				require.Len(t, words, PassphraseWordCountDefault)
+				require.Equal(t, strings.ToLower(words[0]), words[0])
+				for _, word := range words[1:] {
+					require.Equal(t, strings.Title(strings.ToLower(word)), word)
+				}
+			}
+
+			// Casing by position adds no entropy.
+			entropy, err := opts.Entropy()
+			require.NoError(t, err)
+			require.InDelta(t, PassphraseWordCountDefault*math.Log2(5), entropy, 1e-9)
+		},
+	)
+
+	t.Run(
+		"alternating",
+		func(t *testing.T) {
+			opts := PassphraseOptions{
+				Count:     PassphraseCountMax,
+				WordCount: 5,
+				Separator: PassphraseSeparatorDefault,
+				Casing:    PassphraseCasingAlternating,
+				WordList:  []string{"Alfa", "BRAVO", "charlie", "delta", "echo"},
+			}
+
+			passphrases, err := GeneratePassphrasesWithOptions(opts)
+			require.NoError(t, err)
+			for _, passphrase := range passphrases {
+				words := strings.Split(passphrase, PassphraseSeparatorDefault)
+				require.Len(t, words, 5)
+				for i, word := range words {
+					if i%2 == 0 {
+						require.Equal(t, strings.ToLower(word), word)
+					} else {
+						require.Equal(t, strings.ToUpper(word), word)
+					}
+				}
+			}
+
+			entropy, err := opts.Entropy()
+			require.NoError(t, err)
+			require.InDelta(t, 5*math.Log2(5), entropy, 1e-9)
+		},
+	)
+
+	t.Run(
+		"one capital",
+		func(t *testing.T) {
+			// Use a seeded pseudorandom source so the test is reproducible.
+			generator := NewGenerator(rand.New(rand.NewSource(1)))
+
+			opts := PassphraseOptions{
+				Count:     PassphraseCountMax,
+				WordCount: PassphraseWordCountDefault,
+				Separator: PassphraseSeparatorDefault,
+				Casing:    PassphraseCasingOneCapital,
+				WordList:  append([]string{"42"}, wordList...),
+			}
+
+			// Words without a title case would hide the capital.
+			require.Equal(t, uint(len(wordList)), opts.WordListSize())
+
+			passphrases, err := generator.PassphrasesWithOptions(opts)
+			require.NoError(t, err)
+
+			// Tally the position of the capitalized word.
+			counts := map[string]int{}
+			for _, passphrase := range passphrases {
+				words := strings.Split(passphrase, PassphraseSeparatorDefault)
+				require.Len(t, words, PassphraseWordCountDefault)
+
+				capitals := 0
+				for i, word := range words {
+					require.Contains(t, wordList, strings.ToLower(word))
+					if word != strings.ToLower(word) {
+						require.Equal(t, strings.Title(word), word)
+						counts[strconv.Itoa(i)]++
+						capitals++
+					}
+				}
+				require.Equal(t, 1, capitals)
+			}
+
+			// Positions must be selected uniformly.
+			require.Len(t, counts, PassphraseWordCountDefault)
+			require.Less(t, chiSquared(counts, PassphraseWordCountDefault), chiSquaredCritical(PassphraseWordCountDefault-1))
+
+			// The position of the capital adds to the entropy of the words.
+			entropy, err := opts.Entropy()
+			require.NoError(t, err)
+			require.InDelta(
+				t,
+				PassphraseWordCountDefault*math.Log2(float64(len(wordList)))+math.Log2(PassphraseWordCountDefault),
+				entropy,
+				1e-9,
+			)
+		},
+	)
+
+	t.Run(
+		"entropy target",
+		func(t *testing.T) {
+			// Random casings reach the target with fewer words.
+			opts := DefaultPassphraseOptions()
+			opts.MinEntropy = 80
+
+			resolved, err := opts.Resolve()
+			require.NoError(t, err)
+
+			opts.Casing = PassphraseCasingRandomWord
+			random, err := opts.Resolve()
+			require.NoError(t, err)
+			require.Less(t, random.WordCount, resolved.WordCount)
+		},
+	)
+}
+
+func TestPassphraseOptionsLanguage(t *testing.T) {
+	type testDef struct {
+		name     string
+		casing   PassphraseCasing
+		language language.Tag
+		wordList []string
+
+		wordSet []string
+	}
+
+	var tests = []testDef{
+		{
+			"title case",
+			PassphraseCasingTitle,
+			language.Und,
+			[]string{"bRAVO", "élan", "drop-down"},
+
+			[]string{"Bravo", "Élan", "Drop-Down"},
+		},
+		{
+			"dutch title case",
+			PassphraseCasingTitle,
+			language.Dutch,
+			[]string{"ijsland", "ijzer"},
+
+			[]string{"IJsland", "IJzer"},
+		},
+		{
+			"turkish uppercase",
+			PassphraseCasingUpper,
+			language.Turkish,
+			[]string{"istanbul", "ırmak"},
+
+			[]string{"İSTANBUL", "IRMAK"},
+		},
+		{
+			"turkish lowercase",
+			PassphraseCasingLower,
+			language.Turkish,
+			[]string{"İZMİR", "IRMAK"},
+
+			[]string{"izmir", "ırmak"},
+		},
+		{
+			"german uppercase",
+			PassphraseCasingUpper,
+			language.German,
+			[]string{"straße", "fuß", "weiß"},
+
+			[]string{"STRASSE", "FUSS", "WEISS"},
+		},
+		{
+			"words collapse under casing",
+			PassphraseCasingUpper,
+			language.German,
+			[]string{"straße", "strasse", "Strasse", "maß", "masse"},
+
+			[]string{"STRASSE", "MASS", "MASSE"},
+		},
+		{
+			"casings which change length are left out of alternating words",
+			PassphraseCasingAlternating,
+			language.German,
+			[]string{"straße", "strasse", "Fuß", "Hund"},
+
+			[]string{"strasse", "hund"},
+		},
+		{
+			"random word casings collapse",
+			PassphraseCasingRandomWord,
+			language.German,
+			[]string{"straße", "STRASSE"},
+
+			[]string{"straße", "STRASSE", "Straße", "strasse", "Strasse"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name,
+			func(t *testing.T) {
+				opts := PassphraseOptions{
+					Count:     PassphraseCountDefault,
+					WordCount: PassphraseWordCountDefault,
+					Separator: PassphraseSeparatorDefault,
+					Casing:    test.casing,
+					WordList:  test.wordList,
+					Language:  test.language,
+				}
+
+				wordSet, err := opts.wordSet()
+				require.NoError(t, err)
+				require.Equal(t, test.wordSet, wordSet)
+
+				// The reported size and entropy count the words left once they have collapsed.
+				require.Equal(t, uint(len(test.wordSet)), opts.WordListSize())
+				entropy, err := opts.Entropy()
+				require.NoError(t, err)
+				require.InDelta(t, PassphraseWordCountDefault*math.Log2(float64(len(test.wordSet))), entropy, 1e-9)
+			},
+		)
+	}
+}
+
+// splitCapitals splits a string before each uppercase letter.
+func splitCapitals(s string) (words []string) {
+	start := 0
+	for i, char := range s {
+		if i > start && unicode.IsUpper(char) {
+			words = append(words, s[start:i])
+			start = i
+		}
+	}
+
+	return append(words, s[start:])
+}
+
 func BenchmarkGeneratePassphrases(b *testing.B) {
 	type benchmarkDef struct {
 		name      string
 		count     uint
 		wordCount uint
-		separator rune
+		separator string
 		casing    PassphraseCasing
 		wordList  []string
 	}
@@ -1,107 +1,398 @@
 package passgen
 
 import (
-	"fmt"
-	"io"
 	"math"
-	"strings"
+	"math/bits"
 )
 
-// GeneratePasswords generates random passwords based on the configuration provided by the user.
-func GeneratePasswords(
-	count uint, // Number of passwords to generate.
-	length uint, // Length of each generated password.
-	alphabet string, // Alphabet to pull password characters from.
-) (
-	passwords []string, // Generated passwords.
-	err error, // Possible error encountered during password generation.
-) {
+// ClassRequirement requires each generated password to contain a minimum number of characters
+// from a class of characters, such as AlphabetNumeric.
+type ClassRequirement struct {
+	Characters string // Characters belonging to the class.
+	Min        uint   // Fewest characters from the class each password must contain.
+}
+
+// PasswordOptions configures the generation of passwords.
+type PasswordOptions struct {
+	Count        uint               // Number of passwords to generate.
+	Length       uint               // Length of each generated password.
+	Alphabet     string             // Alphabet to pull password characters from.
+	Requirements []ClassRequirement // Character classes each password must include.
+	MinEntropy   float64            // If positive, the entropy target used to choose the length.
+	Policy       Policy             // Policy each password must comply with.
+}
+
+// DefaultPasswordOptions returns password options populated with the package defaults.
+func DefaultPasswordOptions() PasswordOptions {
+	return PasswordOptions{
+		Count:    PasswordCountDefault,
+		Length:   PasswordLengthDefault,
+		Alphabet: AlphabetDefault,
+	}
+}
+
+// Validate reports whether the options can generate passwords, returning one of the errors in
+// errors.go if not.
+func (o PasswordOptions) Validate() error {
+	o, err := o.Resolve()
+	if err != nil {
+		return err
+	}
+
+	_, _, err = o.prepare()
+	return err
+}
+
+// Resolve returns a copy of the options with the length set to the shortest which meets the
+// entropy target. Options without an entropy target are returned unchanged.
+func (o PasswordOptions) Resolve() (PasswordOptions, error) {
+	if !(o.MinEntropy > 0) {
+		return o, nil
+	}
+
+	// Validate the provided alphabet.
+	charSet := uniqueChars(o.Alphabet)
+	if len(charSet) < AlphabetLengthMin {
+		return o, ErrAlphabetTooSmall
+	}
+
+	// Class requirements can only lower the entropy of a password, so begin the search at the
+	// length needed to meet the target without them.
+	length := uint(math.Ceil(o.MinEntropy / math.Log2(float64(len(charSet)))))
+	if length < PasswordLengthMin {
+		length = PasswordLengthMin
+	}
+
+	for o.Length = length; o.Length <= PasswordLengthMax; o.Length++ {
+		classOf, err := o.classes(charSet)
+		if err == ErrRequirementsExceedLength {
+			continue
+		}
+		if err != nil {
+			return o, err
+		}
+
+		if o.entropy(charSet, classOf) >= o.MinEntropy {
+			return o, nil
+		}
+	}
+
+	return o, ErrEntropyUnreachable
+}
+
+// prepare validates the options and returns the deduplicated alphabet along with the class of each
+// required character. The options must already be resolved.
+func (o PasswordOptions) prepare() ([]rune, map[rune]int, error) {
+	charSet, err := o.charSet()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	classOf, err := o.classes(charSet)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Ensure the requirements are likely enough to be met by random generation that the attempt
+	// limit will not be reached.
+	log2Acceptance := o.entropy(charSet, classOf) - float64(o.Length)*math.Log2(float64(len(charSet)))
+	if log2Acceptance < 4-math.Log2(GenerationAttemptsMax) {
+		return nil, nil, ErrRequirementsUnsatisfied
+	}
+
+	// Likewise ensure the policy is likely enough to be met alongside the requirements.
+	if o.Policy.log2Acceptance(o.Length, charSet, o.Requirements) < 4-math.Log2(GenerationAttemptsMax) {
+		return nil, nil, ErrPolicyUnsatisfied
+	}
+
+	return charSet, classOf, nil
+}
+
+// Entropy returns the number of bits of entropy in each password generated with the options,
+// accounting for the passwords discarded for not meeting the class requirements but not for those
+// discarded by the policy.
+func (o PasswordOptions) Entropy() (float64, error) {
+	o, err := o.Resolve()
+	if err != nil {
+		return 0, err
+	}
+
+	charSet, err := o.charSet()
+	if err != nil {
+		return 0, err
+	}
+
+	classOf, err := o.classes(charSet)
+	if err != nil {
+		return 0, err
+	}
+
+	return o.entropy(charSet, classOf), nil
+}
+
+// entropy calculates the entropy of passwords drawn from the deduplicated alphabet, given the class
+// of each required character.
+func (o PasswordOptions) entropy(charSet []rune, classOf map[rune]int) float64 {
+	// Without requirements, every character is chosen independently from the whole alphabet.
+	if len(classOf) == 0 {
+		return float64(o.Length) * math.Log2(float64(len(charSet)))
+	}
+
+	// Otherwise, count the passwords which satisfy every requirement.
+	sizes := make([]uint, len(o.Requirements))
+	for _, class := range classOf {
+		sizes[class]++
+	}
+
+	var (
+		classSizes []uint // Size of each required class within the alphabet.
+		classMins  []uint // Minimum character count of each required class.
+	)
+	for i, requirement := range o.Requirements {
+		if sizes[i] == 0 {
+			continue
+		}
+		classSizes = append(classSizes, sizes[i])
+		classMins = append(classMins, requirement.Min)
+	}
+
+	return log2RequiredCount(o.Length, classSizes, classMins, uint(len(charSet)-len(classOf)))
+}
+
+// classes validates the class requirements against the deduplicated alphabet and returns the class
+// index of each alphabet character which belongs to a required class.
+func (o PasswordOptions) classes(charSet []rune) (map[rune]int, error) {
+	// Determine which characters are in the alphabet.
+	inAlphabet := map[rune]struct{}{}
+	for _, char := range charSet {
+		inAlphabet[char] = struct{}{}
+	}
+
+	var (
+		classOf  = map[rune]int{} // Class index of each alphabet character within a required class.
+		minTotal uint             // Fewest characters required by all classes combined.
+	)
+
+	for i, requirement := range o.Requirements {
+		// Classes without a minimum impose no constraint.
+		if requirement.Min == 0 {
+			continue
+		}
+
+		// Assign the class to each of its characters present in the alphabet.
+		var classSize int
+		for _, char := range requirement.Characters {
+			if _, ok := inAlphabet[char]; !ok {
+				continue
+			}
+			if class, ok := classOf[char]; ok {
+				if class == i {
+					continue
+				}
+				return nil, ErrRequirementsOverlap
+			}
+			classOf[char] = i
+			classSize++
+		}
+
+		// Ensure the class can be drawn from the alphabet.
+		if classSize == 0 {
+			return nil, ErrRequirementNotInAlphabet
+		}
+
+		minTotal += requirement.Min
+	}
+
+	// Ensure the requirements fit within the password length.
+	if minTotal > o.Length {
+		return nil, ErrRequirementsExceedLength
+	}
+
+	return classOf, nil
+}
+
+// satisfies reports whether the password meets the minimum count of every required class.
+func (o PasswordOptions) satisfies(password []rune, classOf map[rune]int) bool {
+	if len(classOf) == 0 {
+		return true
+	}
+
+	counts := make([]uint, len(o.Requirements))
+	for _, char := range password {
+		if class, ok := classOf[char]; ok {
+			counts[class]++
+		}
+	}
+
+	for i, requirement := range o.Requirements {
+		if counts[i] < requirement.Min {
+			return false
+		}
+	}
+
+	return true
+}
+
+// charSet validates the options and returns the deduplicated alphabet, preserving the order in
+// which characters first appear.
+func (o PasswordOptions) charSet() ([]rune, error) {
 	// Validate the supplied count parameter.
-	if count < PasswordCountMin || count > PasswordCountMax {
-		return nil, fmt.Errorf("count must be at least %d and at most %d", PasswordCountMin, PasswordCountMax)
+	if o.Count < PasswordCountMin || o.Count > PasswordCountMax {
+		return nil, &RangeError{"count", PasswordCountMin, PasswordCountMax}
 	}
 
 	// Validate the supplied length parameter.
-	if length < PasswordLengthMin || length > PasswordLengthMax {
-		return nil, fmt.Errorf("length must be at least %d and at most %d", PasswordLengthMin, PasswordLengthMax)
+	if o.Length < PasswordLengthMin || o.Length > PasswordLengthMax {
+		return nil, &RangeError{"length", PasswordLengthMin, PasswordLengthMax}
+	}
+
+	// Validate the provided alphabet.
+	charSet := uniqueChars(o.Alphabet)
+	if len(charSet) < AlphabetLengthMin {
+		return nil, ErrAlphabetTooSmall
+	}
+
+	// Validate the provided policy, ensuring passwords of the length drawn from the alphabet can
+	// comply with it.
+	err := o.Policy.Validate()
+	if err != nil {
+		return nil, err
+	}
+	err = o.Policy.validateLength(o.Length, o.Length)
+	if err != nil {
+		return nil, err
+	}
+	err = o.Policy.validateCharacters(string(charSet))
+	if err != nil {
+		return nil, err
+	}
+	err = o.Policy.validateRequirements(o.Length, string(charSet))
+	if err != nil {
+		return nil, err
 	}
 
-	// Deduplicate the provided alphabet.
+	return charSet, nil
+}
+
+// AlphabetSize returns the number of unique characters in the alphabet.
+func (o PasswordOptions) AlphabetSize() uint {
+	return uint(len(uniqueChars(o.Alphabet)))
+}
+
+// uniqueChars deduplicates the provided alphabet, preserving the order in which characters first
+// appear.
+func uniqueChars(alphabet string) []rune {
 	chars := map[rune]struct{}{}
+	var charSet []rune
 	for _, char := range alphabet {
+		if _, ok := chars[char]; ok {
+			continue
+		}
 		chars[char] = struct{}{}
-	}
-	var charSet []rune
-	for char := range chars {
 		charSet = append(charSet, char)
 	}
 
-	// Validate the provided alphabet.
-	if len(charSet) < AlphabetLengthMin {
-		return nil, fmt.Errorf("alphabet must contain at least %d unique characters", AlphabetLengthMin)
+	return charSet
+}
+
+// GeneratePasswords generates random passwords based on the configuration provided by the user,
+// using the default Generator backed by crypto/rand.
+func GeneratePasswords(
+	count uint, // Number of passwords to generate.
+	length uint, // Length of each generated password.
+	alphabet string, // Alphabet to pull password characters from.
+) (
+	passwords []string, // Generated passwords.
+	err error, // Possible error encountered during password generation.
+) {
+	return defaultGenerator.Passwords(count, length, alphabet)
+}
+
+// GeneratePasswordsWithOptions generates random passwords based on the provided options, using the
+// default Generator backed by crypto/rand.
+func GeneratePasswordsWithOptions(opts PasswordOptions) ([]string, error) {
+	return defaultGenerator.PasswordsWithOptions(opts)
+}
+
+// Passwords generates random passwords based on the configuration provided by the user.
+func (g *Generator) Passwords(
+	count uint, // Number of passwords to generate.
+	length uint, // Length of each generated password.
+	alphabet string, // Alphabet to pull password characters from.
+) (
+	passwords []string, // Generated passwords.
+	err error, // Possible error encountered during password generation.
+) {
+	return g.PasswordsWithOptions(PasswordOptions{
+		Count:    count,
+		Length:   length,
+		Alphabet: alphabet,
+	})
+}
+
+// PasswordsWithOptions generates random passwords based on the provided options.
+//
+// Passwords which do not meet the class requirements or comply with the policy are discarded and
+// generated anew, so the output is uniformly distributed over every password satisfying both.
+func (g *Generator) PasswordsWithOptions(opts PasswordOptions) (passwords []string, err error) {
+	// Choose the password length if an entropy target was provided.
+	opts, err = opts.Resolve()
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate the options and retrieve the deduplicated alphabet along with the class of each
+	// required character.
+	charSet, classOf, err := opts.prepare()
+	if err != nil {
+		return nil, err
 	}
 
 	// Determine how many bytes are needed to represent a password of the specified length in the
-	// provided alphabet.
-	bitsPerChar := uint(math.Ceil(math.Log2(float64(len(charSet)))))
-	bitsPerPassword := bitsPerChar * length
+	// provided alphabet. Rejected character indices are replaced by reading further random data.
+	bitsPerChar := uint(bits.Len(uint(len(charSet) - 1)))
+	bitsPerPassword := bitsPerChar * opts.Length
 	bytesPerPassword := bitsPerPassword / 8
 	if bitsPerPassword%8 > 0 {
 		bytesPerPassword++
 	}
 
 	var (
-		i              uint            // Password counter.
-		b              strings.Builder // String builder for efficiently constructing passwords.
-		passwordBuffer []byte          // Byte buffer for random data used as password source.
+		i        uint                                       // Password counter.
+		attempts uint                                       // Generation attempts for the current password.
+		charIdx  uint                                       // Character index within the provided alphabet.
+		password = make([]rune, opts.Length)                // Buffer for constructing passwords.
+		source   = newBitReader(g.source, bytesPerPassword) // Bit reader for random data used as password source.
 	)
 
-	for i = 0; i < count; i++ {
-		// Read enough random data to sufficiently produce a password.
-		passwordBuffer = make([]byte, bytesPerPassword)
-		_, err = io.ReadFull(randSource, passwordBuffer)
-		if err != nil {
-			return nil, err
-		}
-
-		var (
-			j          uint // Password character counter.
-			charIdx    uint // Character index within the provided alphabet.
-			bitIdx     uint // Source buffer bit counter.
-			byteIdx    uint // Source buffer byte counter.
-			charBitIdx uint // Password character bit counter.
-		)
-
-		for j = 0; j < length; j++ {
-			for charBitIdx = 0; charBitIdx < bitsPerChar; charBitIdx++ {
-				// Left shift the character index to read the next bit.
-				charIdx <<= 1
-
-				// Set the bit from the password source in the character index.
-				charIdx |= uint((passwordBuffer[byteIdx] & (0x80 >> (bitIdx % 8))) >> (7 - (bitIdx % 8)))
-
-				// Increment the bit counter and, if necessary, the byte counter.
-				bitIdx++
-				if bitIdx%8 == 0 {
-					byteIdx++
-				}
+	for i = 0; i < opts.Count; i++ {
+		for attempts = 0; ; attempts++ {
+			// Give up on requirements which are too unlikely to be met.
+			if attempts == GenerationAttemptsMax {
+				return nil, ErrRequirementsUnsatisfied
 			}
 
-			// Ensure the character index is within the bounds of the alphabet.
-			charIdx = charIdx % uint(len(charSet))
+			var j uint // Password character counter.
 
-			// Retrieve the character from the alphabet and write it to the password.
-			b.WriteRune(charSet[charIdx])
+			for j = 0; j < opts.Length; j++ {
+				// Select a uniformly distributed character index within the bounds of the alphabet.
+				charIdx, err = source.readIndex(uint(len(charSet)))
+				if err != nil {
+					return nil, err
+				}
 
-			// Reset character index value.
-			charIdx = 0
+				// Retrieve the character from the alphabet and write it to the password.
+				password[j] = charSet[charIdx]
+			}
+
+			// Discard the password if it does not meet the class requirements or the policy.
+			if opts.satisfies(password, classOf) && opts.Policy.complies(string(password)) {
+				break
+			}
 		}
 
 		// Append the password to the return list.
-		passwords = append(passwords, b.String())
-
-		// Reset the string builder for the next password.
-		b.Reset()
+		passwords = append(passwords, string(password))
 	}
 
 	return
@@ -2,7 +2,9 @@ package passgen
 
 import (
 	"bytes"
-	"io"
+	"errors"
+	"math"
+	"math/rand"
 	"strings"
 	"testing"
 	"unicode/utf8"
@@ -181,12 +183,12 @@ func TestGeneratePasswords(t *testing.T) {
 			},
 
 			func() interface{} {
-				originalRandSource := randSource
-				randSource = new(bytes.Reader)
-				return originalRandSource
+				originalGenerator := defaultGenerator
+				defaultGenerator = NewGenerator(new(bytes.Reader))
+				return originalGenerator
 			},
 			func(setupContext interface{}) {
-				randSource = setupContext.(io.Reader)
+				defaultGenerator = setupContext.(*Generator)
 			},
 		},
 	}
@@ -215,6 +217,374 @@ func TestGeneratePasswords(t *testing.T) {
 	}
 }
 
+func TestPasswordOptions(t *testing.T) {
+	type testDef struct {
+		name    string
+		options PasswordOptions
+
+		requirements func(t *testing.T, err error)
+	}
+
+	var tests = []testDef{
+		{
+			"rational defaults",
+			DefaultPasswordOptions(),
+
+			func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			"count out of range",
+			PasswordOptions{
+				Count:    PasswordCountMax + 1,
+				Length:   PasswordLengthDefault,
+				Alphabet: AlphabetDefault,
+			},
+
+			func(t *testing.T, err error) {
+				var rangeErr *RangeError
+				require.True(t, errors.As(err, &rangeErr))
+				require.Equal(t, "count", rangeErr.Parameter)
+				require.EqualValues(t, PasswordCountMin, rangeErr.Min)
+				require.EqualValues(t, PasswordCountMax, rangeErr.Max)
+			},
+		},
+		{
+			"length out of range",
+			PasswordOptions{
+				Count:    PasswordCountDefault,
+				Length:   PasswordLengthMin - 1,
+				Alphabet: AlphabetDefault,
+			},
+
+			func(t *testing.T, err error) {
+				var rangeErr *RangeError
+				require.True(t, errors.As(err, &rangeErr))
+				require.Equal(t, "length", rangeErr.Parameter)
+			},
+		},
+		{
+			"alphabet too small",
+			PasswordOptions{
+				Count:    PasswordCountDefault,
+				Length:   PasswordLengthDefault,
+				Alphabet: "xx",
+			},
+
+			func(t *testing.T, err error) {
+				require.True(t, errors.Is(err, ErrAlphabetTooSmall))
+			},
+		},
+		{
+			"requirement not in alphabet",
+			PasswordOptions{
+				Count:        PasswordCountDefault,
+				Length:       PasswordLengthDefault,
+				Alphabet:     AlphabetDefault,
+				Requirements: []ClassRequirement{{AlphabetSpecial, 1}},
+			},
+
+			func(t *testing.T, err error) {
+				require.True(t, errors.Is(err, ErrRequirementNotInAlphabet))
+			},
+		},
+		{
+			"overlapping requirements",
+			PasswordOptions{
+				Count:        PasswordCountDefault,
+				Length:       PasswordLengthDefault,
+				Alphabet:     AlphabetDefault,
+				Requirements: []ClassRequirement{{AlphabetLower, 1}, {"a1", 1}},
+			},
+
+			func(t *testing.T, err error) {
+				require.True(t, errors.Is(err, ErrRequirementsOverlap))
+			},
+		},
+		{
+			"requirements exceed length",
+			PasswordOptions{
+				Count:        PasswordCountDefault,
+				Length:       PasswordLengthMin,
+				Alphabet:     AlphabetDefault,
+				Requirements: []ClassRequirement{{AlphabetLower, 3}, {AlphabetNumeric, 3}},
+			},
+
+			func(t *testing.T, err error) {
+				require.True(t, errors.Is(err, ErrRequirementsExceedLength))
+			},
+		},
+		{
+			"requirements without a minimum are ignored",
+			PasswordOptions{
+				Count:        PasswordCountDefault,
+				Length:       PasswordLengthDefault,
+				Alphabet:     AlphabetDefault,
+				Requirements: []ClassRequirement{{AlphabetSpecial, 0}},
+			},
+
+			func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			"requirements too unlikely to be met",
+			PasswordOptions{
+				Count:        PasswordCountDefault,
+				Length:       PasswordLengthMin,
+				Alphabet:     AlphabetDefault,
+				Requirements: []ClassRequirement{{"a", PasswordLengthMin}},
+			},
+
+			func(t *testing.T, err error) {
+				require.True(t, errors.Is(err, ErrRequirementsUnsatisfied))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name,
+			func(t *testing.T) {
+				test.requirements(t, test.options.Validate())
+
+				passwords, err := GeneratePasswordsWithOptions(test.options)
+				test.requirements(t, err)
+				if err == nil {
+					require.Len(t, passwords, int(test.options.Count))
+				} else {
+					require.Empty(t, passwords)
+				}
+			},
+		)
+	}
+}
+
+func TestGeneratePasswordsRequirements(t *testing.T) {
+	// Use a seeded pseudorandom source so the test is reproducible.
+	generator := NewGenerator(rand.New(rand.NewSource(1)))
+
+	opts := PasswordOptions{
+		Count:    PasswordCountMax,
+		Length:   PasswordLengthMin,
+		Alphabet: "abc",
+		Requirements: []ClassRequirement{
+			{"a", 2},
+		},
+	}
+
+	// Tally how often each password was generated.
+	counts := map[string]int{}
+	for i := 0; i < 100; i++ {
+		passwords, err := generator.PasswordsWithOptions(opts)
+		require.NoError(t, err)
+		for _, password := range passwords {
+			require.GreaterOrEqual(t, strings.Count(password, "a"), 2)
+			counts[password]++
+		}
+	}
+
+	// Of the 243 possible passwords, 131 contain at least two of the required character. Each of
+	// them must be generated with equal probability.
+	require.Len(t, counts, 131)
+	require.Less(t, chiSquared(counts, 131), chiSquaredCritical(130))
+}
+
+func TestPasswordOptionsEntropy(t *testing.T) {
+	type testDef struct {
+		name    string
+		options PasswordOptions
+		entropy float64
+	}
+
+	var tests = []testDef{
+		{
+			"rational defaults",
+			DefaultPasswordOptions(),
+			PasswordLengthDefault * math.Log2(float64(len(AlphabetDefault))),
+		},
+		{
+			"duplicated alphabet",
+			PasswordOptions{
+				Count:    PasswordCountDefault,
+				Length:   PasswordLengthDefault,
+				Alphabet: AlphabetDefault + AlphabetDefault,
+			},
+			PasswordLengthDefault * math.Log2(float64(len(AlphabetDefault))),
+		},
+		{
+			"class requirements",
+			PasswordOptions{
+				Count:        PasswordCountDefault,
+				Length:       PasswordLengthMin,
+				Alphabet:     "abc",
+				Requirements: []ClassRequirement{{"a", 2}},
+			},
+			math.Log2(131),
+		},
+		{
+			"every character required",
+			PasswordOptions{
+				Count:        PasswordCountDefault,
+				Length:       PasswordLengthMin,
+				Alphabet:     "ab",
+				Requirements: []ClassRequirement{{"a", 2}, {"b", 3}},
+			},
+			math.Log2(10),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name,
+			func(t *testing.T) {
+				entropy, err := test.options.Entropy()
+				require.NoError(t, err)
+				require.InDelta(t, test.entropy, entropy, 1e-9)
+			},
+		)
+	}
+
+	t.Run(
+		"invalid options",
+		func(t *testing.T) {
+			_, err := PasswordOptions{}.Entropy()
+			require.Error(t, err)
+		},
+	)
+}
+
+func TestPasswordOptionsResolve(t *testing.T) {
+	type testDef struct {
+		name    string
+		options PasswordOptions
+
+		requirements func(t *testing.T, opts PasswordOptions, err error)
+	}
+
+	var tests = []testDef{
+		{
+			"no entropy target",
+			DefaultPasswordOptions(),
+
+			func(t *testing.T, opts PasswordOptions, err error) {
+				require.NoError(t, err)
+				require.Equal(t, DefaultPasswordOptions(), opts)
+			},
+		},
+		{
+			"entropy target",
+			PasswordOptions{
+				Count:      PasswordCountDefault,
+				Alphabet:   AlphabetDefault,
+				MinEntropy: 128,
+			},
+
+			func(t *testing.T, opts PasswordOptions, err error) {
+				require.NoError(t, err)
+				require.EqualValues(t, 22, opts.Length)
+			},
+		},
+		{
+			"entropy target with class requirements",
+			PasswordOptions{
+				Count:        PasswordCountDefault,
+				Alphabet:     AlphabetDefault + AlphabetSpecial,
+				Requirements: []ClassRequirement{{AlphabetSpecial, 4}},
+				MinEntropy:   128,
+			},
+
+			func(t *testing.T, opts PasswordOptions, err error) {
+				require.NoError(t, err)
+
+				// Measure the entropy at the chosen length and the one preceding it.
+				opts.MinEntropy = 0
+				entropy, err := opts.Entropy()
+				require.NoError(t, err)
+				require.GreaterOrEqual(t, entropy, 128.0)
+
+				opts.Length--
+				entropy, err = opts.Entropy()
+				require.NoError(t, err)
+				require.Less(t, entropy, 128.0)
+			},
+		},
+		{
+			"entropy target below the length limit",
+			PasswordOptions{
+				Count:      PasswordCountDefault,
+				Alphabet:   AlphabetDefault,
+				MinEntropy: 1,
+			},
+
+			func(t *testing.T, opts PasswordOptions, err error) {
+				require.NoError(t, err)
+				require.EqualValues(t, PasswordLengthMin, opts.Length)
+			},
+		},
+		{
+			"entropy target beyond the length limit",
+			PasswordOptions{
+				Count:      PasswordCountDefault,
+				Alphabet:   AlphabetDefault,
+				MinEntropy: 10000,
+			},
+
+			func(t *testing.T, opts PasswordOptions, err error) {
+				require.True(t, errors.Is(err, ErrEntropyUnreachable))
+			},
+		},
+		{
+			"entropy target with alphabet too small",
+			PasswordOptions{
+				Count:      PasswordCountDefault,
+				Alphabet:   "x",
+				MinEntropy: 128,
+			},
+
+			func(t *testing.T, opts PasswordOptions, err error) {
+				require.True(t, errors.Is(err, ErrAlphabetTooSmall))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name,
+			func(t *testing.T) {
+				opts, err := test.options.Resolve()
+				test.requirements(t, opts, err)
+			},
+		)
+	}
+}
+
+func TestGeneratePasswordsDistribution(t *testing.T) {
+	// Use a seeded pseudorandom source so the test is reproducible.
+	generator := NewGenerator(rand.New(rand.NewSource(1)))
+
+	passwords, err := generator.Passwords(
+		PasswordCountMax,
+		PasswordLengthMax,
+		AlphabetDefault,
+	)
+	require.NoError(t, err)
+
+	// Tally how often each character of the alphabet was selected.
+	counts := map[string]int{}
+	for _, password := range passwords {
+		for _, char := range password {
+			counts[string(char)]++
+		}
+	}
+
+	// Characters must be selected uniformly. The default alphabet is not a power of two in size,
+	// so reducing random indices modulo its size would fail this check.
+	require.Len(t, counts, len(AlphabetDefault))
+	require.Less(t, chiSquared(counts, len(AlphabetDefault)), chiSquaredCritical(len(AlphabetDefault)-1))
+}
+
 func BenchmarkGeneratePasswords(b *testing.B) {
 	type benchmarkDef struct {
 		name     string
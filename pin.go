@@ -0,0 +1,291 @@
+package passgen
+
+import (
+	"math"
+	"math/bits"
+)
+
+// Commonly chosen PINs, drawn from published analyses of leaked PIN and password datasets. These
+// are rejected by PINOptions.RejectCommon.
+var commonPINs = []string{
+	// Four digits.
+	"1234", "1111", "0000", "1212", "7777", "1004", "2000", "4444", "2222", "6969",
+	"9999", "3333", "5555", "6666", "1122", "1313", "8888", "4321", "2001", "1010",
+	"2580", "0852", "1379", "1470", "1590", "2468", "1357", "1984", "1999", "2020",
+
+	// Five digits.
+	"12345", "54321", "11111", "00000", "13579", "24680",
+
+	// Six digits.
+	"123456", "654321", "111111", "000000", "121212", "112233", "123123", "123321",
+	"159753", "147258", "258369", "696969", "666666", "777777", "520520", "131313",
+
+	// Eight digits.
+	"12345678", "87654321", "11111111", "00000000", "12341234", "11223344", "12121212",
+}
+
+// PINOptions configures the generation of numeric PINs and codes. PIN digits are drawn from the
+// full range of Arabic numerals, including those considered ambiguous in passwords.
+type PINOptions struct {
+	Count  uint // Number of PINs to generate.
+	Length uint // Length, in digits, of each generated PIN.
+
+	RejectRepeated   bool // Reject PINs consisting of a repeated digit or block of digits, e.g. 1111 or 1212.
+	RejectSequential bool // Reject PINs consisting of an ascending or descending run, e.g. 1234 or 9876.
+	RejectCommon     bool // Reject commonly chosen PINs, e.g. 2580 or 1004.
+
+	Policy Policy // Policy each PIN must comply with.
+}
+
+// DefaultPINOptions returns PIN options populated with the package defaults.
+func DefaultPINOptions() PINOptions {
+	return PINOptions{
+		Count:  PINCountDefault,
+		Length: PINLengthDefault,
+	}
+}
+
+// Validate checks the options against the package limits. A *RangeError is returned for an out of
+// bounds count or length, ErrInvalidPolicyLength for a contradictory policy, and one of the policy
+// errors for a policy no PIN of the length complies with, or is likely to.
+func (o PINOptions) Validate() error {
+	// Validate the supplied count parameter.
+	if o.Count < PINCountMin || o.Count > PINCountMax {
+		return &RangeError{"count", PINCountMin, PINCountMax}
+	}
+
+	// Validate the supplied length parameter.
+	if o.Length < PINLengthMin || o.Length > PINLengthMax {
+		return &RangeError{"length", PINLengthMin, PINLengthMax}
+	}
+
+	// Validate the provided policy, ensuring PINs of the length can comply with it.
+	err := o.Policy.Validate()
+	if err != nil {
+		return err
+	}
+	err = o.Policy.validateLength(o.Length, o.Length)
+	if err != nil {
+		return err
+	}
+	err = o.Policy.validateCharacters(AlphabetNumericAmbiguous)
+	if err != nil {
+		return err
+	}
+	err = o.Policy.validateRequirements(o.Length, AlphabetNumericAmbiguous)
+	if err != nil {
+		return err
+	}
+
+	// Ensure PINs, whose digits are drawn uniformly, are likely enough to comply with the policy
+	// that the attempt limit will not be reached.
+	if o.Policy.log2Acceptance(o.Length, []rune(AlphabetNumericAmbiguous), nil) < 4-math.Log2(GenerationAttemptsMax) {
+		return ErrPolicyUnsatisfied
+	}
+
+	return nil
+}
+
+// Entropy returns the number of bits of entropy in each PIN generated with the options, accounting
+// for the PINs rejected by the enabled filters but not by the policy.
+func (o PINOptions) Entropy() (float64, error) {
+	err := o.Validate()
+	if err != nil {
+		return 0, err
+	}
+
+	digits := float64(len(AlphabetNumericAmbiguous))
+	total := math.Pow(digits, float64(o.Length))
+	var rejected float64
+
+	// Count the PINs which repeat a shorter block of digits. Every PIN of the length is a repetition
+	// of a unique primitive block whose length divides it, so the number of PINs which are not
+	// repetitions follows from Möbius inversion.
+	if o.RejectRepeated {
+		var primitive float64
+		var d uint
+		for d = 1; d <= o.Length; d++ {
+			if o.Length%d == 0 {
+				primitive += float64(mobius(d)) * math.Pow(digits, float64(o.Length/d))
+			}
+		}
+		rejected += total - primitive
+	}
+
+	// Count the ascending and descending runs. A run never repeats a digit, so none of them were
+	// already counted as repetitions.
+	if o.RejectSequential && o.Length <= uint(digits) {
+		rejected += 2 * (digits - float64(o.Length) + 1)
+	}
+
+	// Count the common PINs of the length which were not already rejected.
+	if o.RejectCommon {
+		seen := map[string]struct{}{}
+		for _, pin := range commonPINs {
+			if uint(len(pin)) != o.Length {
+				continue
+			}
+			if _, ok := seen[pin]; ok {
+				continue
+			}
+			seen[pin] = struct{}{}
+
+			if (o.RejectRepeated && isRepeatedPIN(pin)) || (o.RejectSequential && isSequentialPIN(pin)) {
+				continue
+			}
+			rejected++
+		}
+	}
+
+	return math.Log2(total - rejected), nil
+}
+
+// rejects reports whether the PIN is rejected by one of the enabled filters or the policy.
+func (o PINOptions) rejects(pin string, common map[string]struct{}) bool {
+	if o.RejectRepeated && isRepeatedPIN(pin) {
+		return true
+	}
+
+	if o.RejectSequential && isSequentialPIN(pin) {
+		return true
+	}
+
+	if o.RejectCommon {
+		if _, ok := common[pin]; ok {
+			return true
+		}
+	}
+
+	return !o.Policy.complies(pin)
+}
+
+// isRepeatedPIN reports whether the PIN consists of a shorter block of digits repeated, such as
+// 1111 or 1212.
+func isRepeatedPIN(pin string) bool {
+	for period := 1; period < len(pin); period++ {
+		if len(pin)%period != 0 {
+			continue
+		}
+
+		repeated := true
+		for i := period; i < len(pin); i++ {
+			if pin[i] != pin[i-period] {
+				repeated = false
+				break
+			}
+		}
+		if repeated {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isSequentialPIN reports whether the PIN is a run of consecutively ascending or descending
+// digits, such as 1234 or 9876.
+func isSequentialPIN(pin string) bool {
+	ascending, descending := true, true
+	for i := 1; i < len(pin); i++ {
+		ascending = ascending && pin[i] == pin[i-1]+1
+		descending = descending && pin[i] == pin[i-1]-1
+	}
+
+	return ascending || descending
+}
+
+// mobius returns the value of the Möbius function for n.
+func mobius(n uint) int {
+	result := 1
+
+	var p uint
+	for p = 2; p*p <= n; p++ {
+		if n%p != 0 {
+			continue
+		}
+		n /= p
+		if n%p == 0 {
+			return 0
+		}
+		result = -result
+	}
+	if n > 1 {
+		result = -result
+	}
+
+	return result
+}
+
+// GeneratePINs generates random PINs based on the provided options, using the default Generator
+// backed by crypto/rand.
+func GeneratePINs(opts PINOptions) ([]string, error) {
+	return defaultGenerator.PINs(opts)
+}
+
+// PINs generates random PINs based on the provided options.
+//
+// PINs rejected by the enabled filters or the policy are discarded and generated anew, so the
+// output is uniformly distributed over every PIN the filters and policy accept.
+func (g *Generator) PINs(opts PINOptions) (pins []string, err error) {
+	// Validate the options.
+	err = opts.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	// Build a lookup of the common PINs.
+	common := map[string]struct{}{}
+	for _, pin := range commonPINs {
+		common[pin] = struct{}{}
+	}
+
+	// Determine how many bytes are needed to represent a PIN of the specified length. Rejected
+	// digit indices are replaced by reading further random data.
+	bitsPerDigit := uint(bits.Len(uint(len(AlphabetNumericAmbiguous) - 1)))
+	bitsPerPIN := bitsPerDigit * opts.Length
+	bytesPerPIN := bitsPerPIN / 8
+	if bitsPerPIN%8 > 0 {
+		bytesPerPIN++
+	}
+
+	var (
+		i        uint                                  // PIN counter.
+		attempts uint                                  // Generation attempts for the current PIN.
+		digitIdx uint                                  // Digit index within the numeric alphabet.
+		pin      = make([]byte, opts.Length)           // Buffer for constructing PINs.
+		source   = newBitReader(g.source, bytesPerPIN) // Bit reader for random data used as PIN source.
+	)
+
+	for i = 0; i < opts.Count; i++ {
+		for attempts = 0; ; attempts++ {
+			// The filters reject a small fraction of PINs, so this limit is only a safeguard unless
+			// the policy rejects most of them.
+			if attempts == GenerationAttemptsMax {
+				return nil, ErrRequirementsUnsatisfied
+			}
+
+			var j uint // PIN digit counter.
+
+			for j = 0; j < opts.Length; j++ {
+				// Select a uniformly distributed digit.
+				digitIdx, err = source.readIndex(uint(len(AlphabetNumericAmbiguous)))
+				if err != nil {
+					return nil, err
+				}
+
+				// Retrieve the digit and write it to the PIN.
+				pin[j] = AlphabetNumericAmbiguous[digitIdx]
+			}
+
+			// Discard the PIN if it is rejected by one of the filters or the policy.
+			if !opts.rejects(string(pin), common) {
+				break
+			}
+		}
+
+		// Append the PIN to the return list.
+		pins = append(pins, string(pin))
+	}
+
+	return
+}
@@ -0,0 +1,171 @@
+package passgen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeneratePINs(t *testing.T) {
+	type testReqs func(t *testing.T, pins []string, err error)
+
+	type testDef struct {
+		name    string
+		options PINOptions
+
+		requirements testReqs
+	}
+
+	var tests = []testDef{
+		{
+			"rational defaults",
+			DefaultPINOptions(),
+
+			func(t *testing.T, pins []string, err error) {
+				require.NoError(t, err)
+				require.Len(t, pins, PINCountDefault)
+				for _, pin := range pins {
+					require.Len(t, pin, PINLengthDefault)
+					for _, digit := range pin {
+						require.Contains(t, AlphabetNumericAmbiguous, string(digit))
+					}
+				}
+			},
+		},
+		{
+			"every filter enabled",
+			PINOptions{
+				Count:            PINCountMax,
+				Length:           PINLengthMin,
+				RejectRepeated:   true,
+				RejectSequential: true,
+				RejectCommon:     true,
+			},
+
+			func(t *testing.T, pins []string, err error) {
+				require.NoError(t, err)
+				require.Len(t, pins, PINCountMax)
+				for _, pin := range pins {
+					require.Len(t, pin, PINLengthMin)
+					require.False(t, isRepeatedPIN(pin))
+					require.False(t, isSequentialPIN(pin))
+					require.NotContains(t, commonPINs, pin)
+				}
+			},
+		},
+		{
+			"count too small",
+			PINOptions{Count: PINCountMin - 1, Length: PINLengthDefault},
+
+			func(t *testing.T, pins []string, err error) {
+				require.Empty(t, pins)
+				var rangeErr *RangeError
+				require.True(t, errors.As(err, &rangeErr))
+			},
+		},
+		{
+			"count too large",
+			PINOptions{Count: PINCountMax + 1, Length: PINLengthDefault},
+
+			func(t *testing.T, pins []string, err error) {
+				require.Empty(t, pins)
+				var rangeErr *RangeError
+				require.True(t, errors.As(err, &rangeErr))
+			},
+		},
+		{
+			"length too small",
+			PINOptions{Count: PINCountDefault, Length: PINLengthMin - 1},
+
+			func(t *testing.T, pins []string, err error) {
+				require.Empty(t, pins)
+				var rangeErr *RangeError
+				require.True(t, errors.As(err, &rangeErr))
+			},
+		},
+		{
+			"length too large",
+			PINOptions{Count: PINCountDefault, Length: PINLengthMax + 1},
+
+			func(t *testing.T, pins []string, err error) {
+				require.Empty(t, pins)
+				var rangeErr *RangeError
+				require.True(t, errors.As(err, &rangeErr))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name,
+			func(t *testing.T) {
+				pins, err := GeneratePINs(test.options)
+				test.requirements(t, pins, err)
+			},
+		)
+	}
+
+	t.Run(
+		"random source EOF",
+		func(t *testing.T) {
+			pins, err := NewGenerator(new(bytes.Reader)).PINs(DefaultPINOptions())
+			require.Empty(t, pins)
+			require.Error(t, err)
+		},
+	)
+}
+
+func TestPINOptionsEntropy(t *testing.T) {
+	type testDef struct {
+		name    string
+		options PINOptions
+	}
+
+	var tests = []testDef{
+		{"no filters", PINOptions{Count: 1, Length: 4}},
+		{"repeated", PINOptions{Count: 1, Length: 6, RejectRepeated: true}},
+		{"sequential", PINOptions{Count: 1, Length: 5, RejectSequential: true}},
+		{"common", PINOptions{Count: 1, Length: 4, RejectCommon: true}},
+		{
+			"every filter",
+			PINOptions{Count: 1, Length: 4, RejectRepeated: true, RejectSequential: true, RejectCommon: true},
+		},
+		{
+			"every filter, longer",
+			PINOptions{Count: 1, Length: 6, RejectRepeated: true, RejectSequential: true, RejectCommon: true},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name,
+			func(t *testing.T) {
+				common := map[string]struct{}{}
+				for _, pin := range commonPINs {
+					common[pin] = struct{}{}
+				}
+
+				// Count the accepted PINs by enumerating every PIN of the length.
+				var accepted float64
+				limit := int(math.Pow(10, float64(test.options.Length)))
+				for n := 0; n < limit; n++ {
+					pin := fmt.Sprintf("%0*d", test.options.Length, n)
+					if !test.options.rejects(pin, common) {
+						accepted++
+					}
+				}
+
+				entropy, err := test.options.Entropy()
+				require.NoError(t, err)
+				require.InDelta(t, math.Log2(accepted), entropy, 1e-9)
+			},
+		)
+	}
+
+	_, err := PINOptions{}.Entropy()
+	require.Error(t, err)
+}
@@ -0,0 +1,388 @@
+package passgen
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// PolicyRule names a rule of a Policy which a secret may violate.
+type PolicyRule string
+
+// Policy describes the composition rules secrets must follow, such as those of an organization's
+// password policy. The zero Policy has no rules, so every secret complies with it.
+//
+// Generators discard secrets which violate the policy of their options and generate them anew, so
+// the output is uniformly distributed over every compliant secret. Options are rejected up front
+// when the lengths or characters of their secrets rule out compliance, such as a policy requiring a
+// character class the alphabet lacks. Password and PIN options are also rejected when compliance is
+// too unlikely for random generation to reach within the attempt limit. The entropy reported by the
+// options does not account for the discarded secrets, so it is an upper bound when a policy is set.
+type Policy struct {
+	MinLength uint // If positive, the fewest characters allowed in a secret.
+	MaxLength uint // If positive, the most characters allowed in a secret.
+
+	Requirements        []ClassRequirement // Character classes a secret must include.
+	ForbiddenCharacters string             // Characters a secret must not contain.
+
+	MaxRepeats  uint // If positive, the most times a character may appear in a row, such as 2 to forbid "aaa".
+	MaxSequence uint // If positive, the longest run of consecutive letters or digits allowed, such as 2 to forbid "abc" or "321".
+
+	DisallowedSubstrings []string // Substrings a secret must not contain regardless of case, such as the username.
+}
+
+// Violation describes how a secret breaks a rule of a Policy.
+type Violation struct {
+	Rule   PolicyRule // Rule the secret breaks.
+	Detail string     // Description of how the secret breaks the rule.
+}
+
+// String returns the rule followed by the description of how it is broken.
+func (v Violation) String() string {
+	return string(v.Rule) + ": " + v.Detail
+}
+
+// Validate checks the policy for rules which contradict one another. ErrInvalidPolicyLength is
+// returned for a minimum length above the maximum.
+func (p Policy) Validate() error {
+	if p.MinLength > 0 && p.MaxLength > 0 && p.MinLength > p.MaxLength {
+		return ErrInvalidPolicyLength
+	}
+
+	return nil
+}
+
+// validateLength checks the policy against secrets whose lengths, in characters, range from
+// minLength to maxLength. ErrPolicyLengthUnreachable is returned when the length limits of the
+// policy exclude every such length.
+func (p Policy) validateLength(minLength uint, maxLength uint) error {
+	if (p.MinLength > 0 && p.MinLength > maxLength) || (p.MaxLength > 0 && p.MaxLength < minLength) {
+		return ErrPolicyLengthUnreachable
+	}
+
+	return nil
+}
+
+// validateRequirements checks the required classes of the policy against secrets of at most
+// maxLength characters drawn from the alphabets. ErrPolicyRequirementsExceedLength is returned when
+// a single class, or the classes sharing no allowed characters combined, need more characters than
+// the longest secret holds.
+func (p Policy) validateRequirements(maxLength uint, alphabets ...string) error {
+	for _, requirement := range p.Requirements {
+		if requirement.Min > maxLength {
+			return ErrPolicyRequirementsExceedLength
+		}
+	}
+
+	// The characters of one disjoint class cannot count towards another, so their minimums add up.
+	var minTotal uint
+	_, classMins, _ := p.disjointClasses(p.allowedChars(alphabets...), nil)
+	for _, classMin := range classMins {
+		minTotal += classMin
+	}
+	if minTotal > maxLength {
+		return ErrPolicyRequirementsExceedLength
+	}
+
+	return nil
+}
+
+// validateCharacters checks the policy against secrets drawing characters from each of the
+// alphabets, such as the consonants and vowels of pronounceable passwords. ErrPolicyForbidsAlphabet
+// is returned when every character of an alphabet is forbidden, and ErrPolicyClassNotInAlphabet
+// when a required class has no allowed characters in any alphabet.
+func (p Policy) validateCharacters(alphabets ...string) error {
+	// Gather the allowed characters of every alphabet, ensuring each alphabet keeps at least one.
+	var allowed strings.Builder
+	for _, alphabet := range alphabets {
+		var kept bool
+		for _, char := range uniqueChars(alphabet) {
+			if strings.ContainsRune(p.ForbiddenCharacters, char) {
+				continue
+			}
+			allowed.WriteRune(char)
+			kept = true
+		}
+		if !kept {
+			return ErrPolicyForbidsAlphabet
+		}
+	}
+
+	// Ensure every required class can be drawn from the allowed characters.
+	for _, requirement := range p.Requirements {
+		if requirement.Min > 0 && !strings.ContainsAny(allowed.String(), requirement.Characters) {
+			return ErrPolicyClassNotInAlphabet
+		}
+	}
+
+	return nil
+}
+
+// log2Acceptance returns an upper bound on log2 of the probability that a secret of the length, with
+// every character drawn independently and uniformly from the deduplicated alphabet, complies with
+// the policy while also meeting the requirements. The forbidden characters, required classes,
+// repeats and sequences are accounted for, but not the length limits or disallowed substrings.
+func (p Policy) log2Acceptance(length uint, charSet []rune, requirements []ClassRequirement) float64 {
+	allowed := p.allowedChars(string(charSet))
+
+	// Count the secrets of allowed characters which meet the minimums of the disjoint classes.
+	sizes, mins, others := p.disjointClasses(allowed, requirements)
+	bound := log2RequiredCount(length, sizes, mins, others) - float64(length)*math.Log2(float64(len(charSet)))
+
+	// Count the secrets of allowed characters within the repeat limit.
+	if p.MaxRepeats > 0 {
+		repeats := log2RunLimitedCount(length, uint(len(allowed)), p.MaxRepeats) - float64(length)*math.Log2(float64(len(charSet)))
+		bound = math.Min(bound, repeats)
+	}
+
+	// A secret within the sequence limit has no block of one more character than the limit forming
+	// a sequence. Splitting the secret into such blocks, which are drawn independently, bounds the
+	// probability of compliance by that of every block avoiding a sequence.
+	if blocks := length / (p.MaxSequence + 1); p.MaxSequence > 0 && blocks > 0 {
+		ascending := runProbability(allowed, p.MaxSequence+1, func(prev rune, next rune) bool { return isConsecutive(prev, next, 1) })
+		descending := runProbability(allowed, p.MaxSequence+1, func(prev rune, next rune) bool { return isConsecutive(prev, next, -1) })
+		log2Allowed := math.Log2(float64(len(allowed))) - math.Log2(float64(len(charSet)))
+		bound = math.Min(bound, float64(length)*log2Allowed+float64(blocks)*math.Log2(1-ascending-descending))
+	}
+
+	return bound
+}
+
+// allowedChars returns the deduplicated characters of the alphabets which the policy does not
+// forbid.
+func (p Policy) allowedChars(alphabets ...string) []rune {
+	var allowed []rune
+	for _, char := range uniqueChars(strings.Join(alphabets, "")) {
+		if !strings.ContainsRune(p.ForbiddenCharacters, char) {
+			allowed = append(allowed, char)
+		}
+	}
+
+	return allowed
+}
+
+// disjointClasses returns the number of allowed characters in, and the minimum count of, each class
+// required by the requirements followed by the policy, along with the number of allowed characters
+// in no class. Classes with the same allowed characters are merged, keeping the larger minimum,
+// while classes sharing only some of their characters with an earlier class are left out. Leaving
+// a class out only loosens the requirements, so counts based on the result are upper bounds.
+func (p Policy) disjointClasses(allowed []rune, requirements []ClassRequirement) (sizes []uint, mins []uint, others uint) {
+	// Track the class of each allowed character, with -1 for those in no class yet.
+	classOf := map[rune]int{}
+	for _, char := range allowed {
+		classOf[char] = -1
+	}
+	others = uint(len(allowed))
+
+	for _, requirement := range append(append([]ClassRequirement{}, requirements...), p.Requirements...) {
+		// Classes without a minimum impose no constraint.
+		if requirement.Min == 0 {
+			continue
+		}
+
+		// Gather the allowed characters of the class, tallying the classes they already belong to.
+		var members []rune
+		held := map[int]uint{}
+		for _, char := range uniqueChars(requirement.Characters) {
+			class, ok := classOf[char]
+			if !ok {
+				continue
+			}
+			members = append(members, char)
+			held[class]++
+		}
+
+		switch {
+		case held[-1] == uint(len(members)):
+			// None of the characters belong to a class yet, so the class is disjoint from the rest.
+			// A class without allowed characters is kept, as no secret can meet its minimum.
+			for _, char := range members {
+				classOf[char] = len(sizes)
+			}
+			sizes = append(sizes, uint(len(members)))
+			mins = append(mins, requirement.Min)
+			others -= uint(len(members))
+		case len(held) == 1:
+			// Every character belongs to a single earlier class, which is merged if it has the
+			// same characters.
+			class := classOf[members[0]]
+			if sizes[class] == uint(len(members)) && requirement.Min > mins[class] {
+				mins[class] = requirement.Min
+			}
+		}
+	}
+
+	return sizes, mins, others
+}
+
+// runProbability returns the probability that length characters, drawn independently and
+// uniformly from the deduplicated characters, form a run in which each character follows on from
+// the one before it.
+func runProbability(chars []rune, length uint, follows func(prev rune, next rune) bool) float64 {
+	// Determine which characters may follow on from each character.
+	successors := make([][]int, len(chars))
+	for i, prev := range chars {
+		for j, next := range chars {
+			if follows(prev, next) {
+				successors[i] = append(successors[i], j)
+			}
+		}
+	}
+
+	// probs[i] holds the probability that the characters drawn so far form a run ending in chars[i].
+	probs := make([]float64, len(chars))
+	for i := range probs {
+		probs[i] = 1 / float64(len(chars))
+	}
+
+	var drawn uint
+	for drawn = 1; drawn < length; drawn++ {
+		next := make([]float64, len(chars))
+		for i, prob := range probs {
+			for _, j := range successors[i] {
+				next[j] += prob / float64(len(chars))
+			}
+		}
+		probs = next
+	}
+
+	var total float64
+	for _, prob := range probs {
+		total += prob
+	}
+
+	return total
+}
+
+// Check returns every violation of the policy by the secret, or nil if the secret complies.
+// Lengths are counted in characters. Repeats and sequences are reported once each, for the first
+// offending run, without quoting the secret.
+func (p Policy) Check(secret string) (violations []Violation) {
+	// Check the length of the secret.
+	length := uint(utf8.RuneCountInString(secret))
+	if p.MinLength > 0 && length < p.MinLength {
+		violations = append(violations, Violation{
+			PolicyRuleMinLength,
+			fmt.Sprintf("secret has %d characters, fewer than %d", length, p.MinLength),
+		})
+	}
+	if p.MaxLength > 0 && length > p.MaxLength {
+		violations = append(violations, Violation{
+			PolicyRuleMaxLength,
+			fmt.Sprintf("secret has %d characters, more than %d", length, p.MaxLength),
+		})
+	}
+
+	// Check the secret includes enough characters from each required class.
+	for _, requirement := range p.Requirements {
+		if requirement.Min == 0 {
+			continue
+		}
+
+		var count uint
+		for _, char := range secret {
+			if strings.ContainsRune(requirement.Characters, char) {
+				count++
+			}
+		}
+		if count < requirement.Min {
+			violations = append(violations, Violation{
+				PolicyRuleRequiredClass,
+				fmt.Sprintf("secret has %d characters from %q, fewer than %d", count, requirement.Characters, requirement.Min),
+			})
+		}
+	}
+
+	// Check the secret contains none of the forbidden characters, listing each one it contains.
+	if p.ForbiddenCharacters != "" {
+		var forbidden []rune
+		for _, char := range uniqueChars(secret) {
+			if strings.ContainsRune(p.ForbiddenCharacters, char) {
+				forbidden = append(forbidden, char)
+			}
+		}
+		if len(forbidden) > 0 {
+			violations = append(violations, Violation{
+				PolicyRuleForbiddenCharacter,
+				fmt.Sprintf("secret contains the forbidden characters %q", string(forbidden)),
+			})
+		}
+	}
+
+	// Check the runs of repeated and consecutive characters.
+	chars := []rune(secret)
+	if p.MaxRepeats > 0 {
+		if run := longestRun(chars, isRepeat); run > p.MaxRepeats {
+			violations = append(violations, Violation{
+				PolicyRuleRepeats,
+				fmt.Sprintf("secret repeats a character %d times in a row, more than %d", run, p.MaxRepeats),
+			})
+		}
+	}
+	if p.MaxSequence > 0 {
+		ascending := longestRun(chars, func(prev rune, next rune) bool { return isConsecutive(prev, next, 1) })
+		descending := longestRun(chars, func(prev rune, next rune) bool { return isConsecutive(prev, next, -1) })
+		if descending > ascending {
+			ascending = descending
+		}
+		if ascending > p.MaxSequence {
+			violations = append(violations, Violation{
+				PolicyRuleSequence,
+				fmt.Sprintf("secret contains a sequence of %d consecutive characters, more than %d", ascending, p.MaxSequence),
+			})
+		}
+	}
+
+	// Check the secret contains none of the disallowed substrings, ignoring case.
+	lower := strings.ToLower(secret)
+	for _, substring := range p.DisallowedSubstrings {
+		if substring != "" && strings.Contains(lower, strings.ToLower(substring)) {
+			violations = append(violations, Violation{
+				PolicyRuleSubstring,
+				fmt.Sprintf("secret contains %q", substring),
+			})
+		}
+	}
+
+	return
+}
+
+// complies reports whether the secret complies with the policy.
+func (p Policy) complies(secret string) bool {
+	return len(p.Check(secret)) == 0
+}
+
+// longestRun returns the length of the longest run of characters in which each character follows
+// on from the one before it.
+func longestRun(chars []rune, follows func(prev rune, next rune) bool) uint {
+	var longest, run uint
+	for i := range chars {
+		if i > 0 && follows(chars[i-1], chars[i]) {
+			run++
+		} else {
+			run = 1
+		}
+		if run > longest {
+			longest = run
+		}
+	}
+
+	return longest
+}
+
+// isRepeat reports whether the characters are the same.
+func isRepeat(prev rune, next rune) bool {
+	return prev == next
+}
+
+// isConsecutive reports whether both characters are letters or both are digits, and the next
+// follows the previous by the step in alphabetical or numerical order, ignoring case.
+func isConsecutive(prev rune, next rune, step rune) bool {
+	if !(unicode.IsLetter(prev) && unicode.IsLetter(next)) && !(unicode.IsDigit(prev) && unicode.IsDigit(next)) {
+		return false
+	}
+
+	return unicode.ToLower(next)-unicode.ToLower(prev) == step
+}
@@ -0,0 +1,637 @@
+package passgen
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicyCheck(t *testing.T) {
+	type testDef struct {
+		name   string
+		policy Policy
+		secret string
+
+		rules []PolicyRule
+	}
+
+	var tests = []testDef{
+		{
+			"zero policy",
+			Policy{},
+			"",
+
+			nil,
+		},
+		{
+			"compliant secret",
+			Policy{
+				MinLength: 8,
+				MaxLength: 16,
+				Requirements: []ClassRequirement{
+					{AlphabetLowerAmbiguous, 1},
+					{AlphabetNumericAmbiguous, 2},
+				},
+				ForbiddenCharacters:  " ",
+				MaxRepeats:           2,
+				MaxSequence:          2,
+				DisallowedSubstrings: []string{"alice"},
+			},
+			"correct7horse9",
+
+			nil,
+		},
+		{
+			"too short",
+			Policy{MinLength: 8},
+			"horse",
+
+			[]PolicyRule{PolicyRuleMinLength},
+		},
+		{
+			"length counted in characters",
+			Policy{MinLength: 6, MaxLength: 6},
+			"señora",
+
+			nil,
+		},
+		{
+			"too long",
+			Policy{MaxLength: 8},
+			"correcthorse",
+
+			[]PolicyRule{PolicyRuleMaxLength},
+		},
+		{
+			"missing classes",
+			Policy{
+				Requirements: []ClassRequirement{
+					{AlphabetUpperAmbiguous, 1},
+					{AlphabetNumericAmbiguous, 2},
+					{AlphabetSpecial, 0},
+				},
+			},
+			"correct7horse",
+
+			[]PolicyRule{PolicyRuleRequiredClass, PolicyRuleRequiredClass},
+		},
+		{
+			"forbidden characters",
+			Policy{ForbiddenCharacters: " '\""},
+			"correct horse's",
+
+			[]PolicyRule{PolicyRuleForbiddenCharacter},
+		},
+		{
+			"repeats",
+			Policy{MaxRepeats: 2},
+			"baaad",
+
+			[]PolicyRule{PolicyRuleRepeats},
+		},
+		{
+			"repeats within limit",
+			Policy{MaxRepeats: 3},
+			"baaad",
+
+			nil,
+		},
+		{
+			"ascending letters",
+			Policy{MaxSequence: 2},
+			"xaBcx",
+
+			[]PolicyRule{PolicyRuleSequence},
+		},
+		{
+			"descending digits",
+			Policy{MaxSequence: 3},
+			"pass4321",
+
+			[]PolicyRule{PolicyRuleSequence},
+		},
+		{
+			"sequence within limit",
+			Policy{MaxSequence: 3},
+			"abc-123",
+
+			nil,
+		},
+		{
+			"sequence across letters and digits",
+			Policy{MaxSequence: 2},
+			"9:a",
+
+			nil,
+		},
+		{
+			"disallowed substring",
+			Policy{DisallowedSubstrings: []string{"alice", "example", ""}},
+			"Alice2024!",
+
+			[]PolicyRule{PolicyRuleSubstring},
+		},
+		{
+			"every rule",
+			Policy{
+				MinLength: 16,
+				Requirements: []ClassRequirement{
+					{AlphabetSpecial, 1},
+				},
+				ForbiddenCharacters:  "0",
+				MaxRepeats:           1,
+				MaxSequence:          2,
+				DisallowedSubstrings: []string{"bob"},
+			},
+			"bob1230",
+
+			[]PolicyRule{
+				PolicyRuleMinLength,
+				PolicyRuleRequiredClass,
+				PolicyRuleForbiddenCharacter,
+				PolicyRuleSequence,
+				PolicyRuleSubstring,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name,
+			func(t *testing.T) {
+				violations := test.policy.Check(test.secret)
+
+				var rules []PolicyRule
+				for _, violation := range violations {
+					require.NotEmpty(t, violation.Detail)
+					require.True(t, strings.HasPrefix(violation.String(), string(violation.Rule)+": "))
+					rules = append(rules, violation.Rule)
+				}
+				require.Equal(t, test.rules, rules)
+			},
+		)
+	}
+}
+
+func TestPolicyValidate(t *testing.T) {
+	require.NoError(t, Policy{}.Validate())
+	require.NoError(t, Policy{MinLength: 8, MaxLength: 8}.Validate())
+	require.NoError(t, Policy{MinLength: 8}.Validate())
+	require.True(t, errors.Is(Policy{MinLength: 9, MaxLength: 8}.Validate(), ErrInvalidPolicyLength))
+
+	// Options carrying a contradictory policy are rejected.
+	policy := Policy{MinLength: 9, MaxLength: 8}
+
+	passwordOptions := DefaultPasswordOptions()
+	passwordOptions.Policy = policy
+	require.True(t, errors.Is(passwordOptions.Validate(), ErrInvalidPolicyLength))
+
+	passphraseOptions := DefaultPassphraseOptions()
+	passphraseOptions.Policy = policy
+	require.True(t, errors.Is(passphraseOptions.Validate(), ErrInvalidPolicyLength))
+
+	pronounceableOptions := DefaultPronounceableOptions()
+	pronounceableOptions.Policy = policy
+	require.True(t, errors.Is(pronounceableOptions.Validate(), ErrInvalidPolicyLength))
+
+	pinOptions := DefaultPINOptions()
+	pinOptions.Policy = policy
+	require.True(t, errors.Is(pinOptions.Validate(), ErrInvalidPolicyLength))
+}
+
+func TestGenerateWithPolicy(t *testing.T) {
+	t.Run(
+		"passwords",
+		func(t *testing.T) {
+			// Use a seeded pseudorandom source so the test is reproducible.
+			generator := NewGenerator(rand.New(rand.NewSource(1)))
+
+			opts := PasswordOptions{
+				Count:    PasswordCountMax,
+				Length:   PasswordLengthMin,
+				Alphabet: "abc",
+				Policy:   Policy{MaxRepeats: 1},
+			}
+
+			// Tally how often each password was generated.
+			counts := map[string]int{}
+			for i := 0; i < 100; i++ {
+				passwords, err := generator.PasswordsWithOptions(opts)
+				require.NoError(t, err)
+				for _, password := range passwords {
+					require.Empty(t, opts.Policy.Check(password))
+					counts[password]++
+				}
+			}
+
+			// Of the 243 possible passwords, 48 never repeat a character. Each of them must be
+			// generated with equal probability.
+			require.Len(t, counts, 48)
+			require.Less(t, chiSquared(counts, 48), chiSquaredCritical(47))
+		},
+	)
+
+	t.Run(
+		"passphrases",
+		func(t *testing.T) {
+			opts := PassphraseOptions{
+				Count:     PassphraseCountMax,
+				WordCount: PassphraseWordCountMin,
+				Separator: PassphraseSeparatorDefault,
+				Casing:    PassphraseCasingNone,
+				WordList:  []string{"alfa", "bravo", "charlie", "delta", "echo"},
+				Digits:    1,
+				Policy: Policy{
+					MinLength:            16,
+					DisallowedSubstrings: []string{"echo"},
+					ForbiddenCharacters:  "0",
+				},
+			}
+
+			passphrases, err := GeneratePassphrasesWithOptions(opts)
+			require.NoError(t, err)
+			require.Len(t, passphrases, PassphraseCountMax)
+			for _, passphrase := range passphrases {
+				require.Empty(t, opts.Policy.Check(passphrase))
+			}
+		},
+	)
+
+	t.Run(
+		"pronounceable",
+		func(t *testing.T) {
+			opts := DefaultPronounceableOptions()
+			opts.Count = PasswordCountMax
+			opts.Policy = Policy{DisallowedSubstrings: []string{"ba", "ko"}}
+
+			passwords, err := GeneratePronounceable(opts)
+			require.NoError(t, err)
+			require.Len(t, passwords, PasswordCountMax)
+			for _, password := range passwords {
+				require.Empty(t, opts.Policy.Check(password))
+			}
+		},
+	)
+
+	t.Run(
+		"PINs",
+		func(t *testing.T) {
+			opts := DefaultPINOptions()
+			opts.Count = PINCountMax
+			opts.Policy = Policy{MaxRepeats: 1, MaxSequence: 2}
+
+			pins, err := GeneratePINs(opts)
+			require.NoError(t, err)
+			require.Len(t, pins, PINCountMax)
+			for _, pin := range pins {
+				require.Empty(t, opts.Policy.Check(pin))
+			}
+		},
+	)
+
+	t.Run(
+		"derived passwords",
+		func(t *testing.T) {
+			opts := DefaultDeriveOptions("example.com", "alice")
+			opts.Iterations = DeriveIterationsMin
+			opts.Policy = Policy{MaxRepeats: 1, MaxSequence: 1, DisallowedSubstrings: []string{"a"}}
+
+			// Derivation with a policy remains deterministic.
+			password, err := DerivePassword([]byte("master"), opts)
+			require.NoError(t, err)
+			require.Empty(t, opts.Policy.Check(password))
+
+			again, err := DerivePassword([]byte("master"), opts)
+			require.NoError(t, err)
+			require.Equal(t, password, again)
+		},
+	)
+}
+
+func TestPolicyUnsatisfiable(t *testing.T) {
+	type testDef struct {
+		name     string
+		validate func(policy Policy) error
+		policy   Policy
+
+		err error
+	}
+
+	// Build the validation of each kind of options with the policy.
+	passwordOver := func(alphabet string, length uint) func(policy Policy) error {
+		return func(policy Policy) error {
+			opts := DefaultPasswordOptions()
+			opts.Alphabet = alphabet
+			opts.Length = length
+			opts.Policy = policy
+			return opts.Validate()
+		}
+	}
+	password := func(length uint) func(policy Policy) error {
+		return passwordOver(AlphabetDefault, length)
+	}
+	pin := func(policy Policy) error {
+		opts := DefaultPINOptions()
+		opts.Policy = policy
+		return opts.Validate()
+	}
+	pronounceable := func(policy Policy) error {
+		opts := DefaultPronounceableOptions()
+		opts.Policy = policy
+		return opts.Validate()
+	}
+	derive := func(policy Policy) error {
+		opts := DefaultDeriveOptions("example.com", "alice")
+		opts.Policy = policy
+		return opts.Validate()
+	}
+	passphrase := func(casing PassphraseCasing, digits uint) func(policy Policy) error {
+		return func(policy Policy) error {
+			opts := DefaultPassphraseOptions()
+			opts.WordList = []string{"alfa", "bravo", "charlie", "delta", "echo"}
+			opts.Casing = casing
+			opts.Digits = digits
+			opts.Policy = policy
+			return opts.Validate()
+		}
+	}
+
+	var tests = []testDef{
+		{
+			"password too long",
+			password(PasswordLengthDefault),
+			Policy{MaxLength: PasswordLengthDefault - 1},
+
+			ErrPolicyLengthUnreachable,
+		},
+		{
+			"password too short",
+			password(PasswordLengthDefault),
+			Policy{MinLength: PasswordLengthDefault + 1},
+
+			ErrPolicyLengthUnreachable,
+		},
+		{
+			"password length within limits",
+			password(PasswordLengthDefault),
+			Policy{MinLength: PasswordLengthDefault, MaxLength: PasswordLengthDefault},
+
+			nil,
+		},
+		{
+			"password class not in alphabet",
+			password(256),
+			Policy{Requirements: []ClassRequirement{{AlphabetSpecial, 1}}},
+
+			ErrPolicyClassNotInAlphabet,
+		},
+		{
+			"password class forbidden",
+			password(PasswordLengthDefault),
+			Policy{Requirements: []ClassRequirement{{"abc", 1}}, ForbiddenCharacters: "abc"},
+
+			ErrPolicyClassNotInAlphabet,
+		},
+		{
+			"password class without minimum",
+			password(PasswordLengthDefault),
+			Policy{Requirements: []ClassRequirement{{AlphabetSpecial, 0}}},
+
+			nil,
+		},
+		{
+			"password class exceeds length",
+			password(PasswordLengthDefault),
+			Policy{Requirements: []ClassRequirement{{AlphabetNumeric, PasswordLengthDefault + 1}}},
+
+			ErrPolicyRequirementsExceedLength,
+		},
+		{
+			"password disjoint classes exceed length",
+			passwordOver("abcdef", 10),
+			Policy{Requirements: []ClassRequirement{{"a", 6}, {"b", 6}}},
+
+			ErrPolicyRequirementsExceedLength,
+		},
+		{
+			"password disjoint classes unlikely",
+			passwordOver("abcdef", 10),
+			Policy{Requirements: []ClassRequirement{{"a", 5}, {"b", 5}}},
+
+			ErrPolicyUnsatisfied,
+		},
+		{
+			"password overlapping classes",
+			passwordOver("abcdef", 10),
+			Policy{Requirements: []ClassRequirement{{"ab", 6}, {"bc", 6}}},
+
+			nil,
+		},
+		{
+			"password classes alongside requirements unlikely",
+			func(policy Policy) error {
+				opts := DefaultPasswordOptions()
+				opts.Alphabet = "abcdef"
+				opts.Length = 10
+				opts.Requirements = []ClassRequirement{{"a", 5}}
+				opts.Policy = policy
+				return opts.Validate()
+			},
+			Policy{Requirements: []ClassRequirement{{"b", 5}}},
+
+			ErrPolicyUnsatisfied,
+		},
+		{
+			"password repeats unlikely",
+			passwordOver("ab", 200),
+			Policy{MaxRepeats: 1},
+
+			ErrPolicyUnsatisfied,
+		},
+		{
+			"password repeats within reach",
+			passwordOver("abc", 20),
+			Policy{MaxRepeats: 1},
+
+			nil,
+		},
+		{
+			"password repeats of the only allowed character",
+			passwordOver("ab", PasswordLengthDefault),
+			Policy{MaxRepeats: 2, ForbiddenCharacters: "b"},
+
+			ErrPolicyUnsatisfied,
+		},
+		{
+			"password sequences unlikely",
+			passwordOver("ab", 200),
+			Policy{MaxSequence: 1},
+
+			ErrPolicyUnsatisfied,
+		},
+		{
+			"password alphabet forbidden",
+			password(PasswordLengthDefault),
+			Policy{ForbiddenCharacters: AlphabetDefault},
+
+			ErrPolicyForbidsAlphabet,
+		},
+		{
+			"PIN length",
+			pin,
+			Policy{MinLength: PINLengthDefault + 1},
+
+			ErrPolicyLengthUnreachable,
+		},
+		{
+			"PIN class",
+			pin,
+			Policy{Requirements: []ClassRequirement{{AlphabetLower, 1}}},
+
+			ErrPolicyClassNotInAlphabet,
+		},
+		{
+			"PIN disjoint classes exceed length",
+			pin,
+			Policy{Requirements: []ClassRequirement{{"01", 3}, {"23", 4}}},
+
+			ErrPolicyRequirementsExceedLength,
+		},
+		{
+			"PIN repeats of the only allowed digit",
+			pin,
+			Policy{MaxRepeats: 1, ForbiddenCharacters: "123456789"},
+
+			ErrPolicyUnsatisfied,
+		},
+		{
+			"PIN digits forbidden",
+			pin,
+			Policy{ForbiddenCharacters: AlphabetNumericAmbiguous},
+
+			ErrPolicyForbidsAlphabet,
+		},
+		{
+			"pronounceable length",
+			pronounceable,
+			Policy{MaxLength: PasswordLengthDefault - 1},
+
+			ErrPolicyLengthUnreachable,
+		},
+		{
+			"pronounceable class",
+			pronounceable,
+			Policy{Requirements: []ClassRequirement{{AlphabetUpper, 1}}},
+
+			ErrPolicyClassNotInAlphabet,
+		},
+		{
+			"pronounceable disjoint classes exceed length",
+			pronounceable,
+			Policy{Requirements: []ClassRequirement{{"a", 9}, {"b", 9}}},
+
+			ErrPolicyRequirementsExceedLength,
+		},
+		{
+			"pronounceable vowels forbidden",
+			pronounceable,
+			Policy{ForbiddenCharacters: AlphabetVowel},
+
+			ErrPolicyForbidsAlphabet,
+		},
+		{
+			"derived password length",
+			derive,
+			Policy{MaxLength: PasswordLengthDefault - 1},
+
+			ErrPolicyLengthUnreachable,
+		},
+		{
+			"passphrase too long",
+			passphrase(PassphraseCasingNone, 0),
+			Policy{MaxLength: 12},
+
+			ErrPolicyLengthUnreachable,
+		},
+		{
+			"passphrase too short",
+			passphrase(PassphraseCasingNone, 0),
+			Policy{MinLength: 1000},
+
+			ErrPolicyLengthUnreachable,
+		},
+		{
+			"passphrase length within limits",
+			passphrase(PassphraseCasingNone, 0),
+			Policy{MinLength: 30, MaxLength: 30},
+
+			nil,
+		},
+		{
+			"passphrase class",
+			passphrase(PassphraseCasingNone, 0),
+			Policy{Requirements: []ClassRequirement{{AlphabetNumeric, 1}}},
+
+			ErrPolicyClassNotInAlphabet,
+		},
+		{
+			"passphrase class from digits",
+			passphrase(PassphraseCasingNone, 1),
+			Policy{Requirements: []ClassRequirement{{AlphabetNumeric, 1}}},
+
+			nil,
+		},
+		{
+			"passphrase class from casing",
+			passphrase(PassphraseCasingCamelCase, 0),
+			Policy{Requirements: []ClassRequirement{{AlphabetUpper, 1}}},
+
+			nil,
+		},
+		{
+			"passphrase disjoint classes exceed length",
+			passphrase(PassphraseCasingNone, 0),
+			Policy{Requirements: []ClassRequirement{{"a", 30}, {"e", 30}}},
+
+			ErrPolicyRequirementsExceedLength,
+		},
+		{
+			"passphrase separator forbidden",
+			passphrase(PassphraseCasingNone, 0),
+			Policy{ForbiddenCharacters: PassphraseSeparatorDefault},
+
+			ErrPolicyForbidsAlphabet,
+		},
+		{
+			"passphrase words forbidden",
+			passphrase(PassphraseCasingNone, 0),
+			Policy{ForbiddenCharacters: "ae"},
+
+			ErrPolicyForbidsAlphabet,
+		},
+		{
+			"passphrase words partly forbidden",
+			passphrase(PassphraseCasingNone, 0),
+			Policy{ForbiddenCharacters: "a"},
+
+			nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name,
+			func(t *testing.T) {
+				err := test.validate(test.policy)
+				if test.err == nil {
+					require.NoError(t, err)
+				} else {
+					require.True(t, errors.Is(err, test.err), "unexpected error: %v", err)
+				}
+			},
+		)
+	}
+}
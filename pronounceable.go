@@ -0,0 +1,179 @@
+package passgen
+
+import (
+	"math"
+	"math/bits"
+)
+
+// PronounceableOptions configures the generation of pronounceable passwords. Pronounceable
+// passwords alternate between consonants drawn from AlphabetConsonant and vowels drawn from
+// AlphabetVowel, forming syllables which are easily read aloud.
+type PronounceableOptions struct {
+	Count      uint    // Number of passwords to generate.
+	Length     uint    // Length of each generated password.
+	MinEntropy float64 // If positive, the entropy target used to choose the length.
+	Policy     Policy  // Policy each password must comply with.
+}
+
+// DefaultPronounceableOptions returns pronounceable password options populated with the package
+// defaults.
+func DefaultPronounceableOptions() PronounceableOptions {
+	return PronounceableOptions{
+		Count:  PasswordCountDefault,
+		Length: PasswordLengthDefault,
+	}
+}
+
+// Validate checks the options against the package limits. A *RangeError is returned for an out of
+// bounds count or length, ErrEntropyUnreachable for an entropy target beyond the length limit,
+// ErrInvalidPolicyLength for a contradictory policy, and one of the policy errors for a policy no
+// password of the length complies with.
+func (o PronounceableOptions) Validate() error {
+	o, err := o.Resolve()
+	if err != nil {
+		return err
+	}
+
+	// Validate the supplied count parameter.
+	if o.Count < PasswordCountMin || o.Count > PasswordCountMax {
+		return &RangeError{"count", PasswordCountMin, PasswordCountMax}
+	}
+
+	// Validate the supplied length parameter.
+	if o.Length < PasswordLengthMin || o.Length > PasswordLengthMax {
+		return &RangeError{"length", PasswordLengthMin, PasswordLengthMax}
+	}
+
+	// Validate the provided policy, ensuring passwords of the length, which always draw from both
+	// the consonants and the vowels, can comply with it.
+	err = o.Policy.Validate()
+	if err != nil {
+		return err
+	}
+	err = o.Policy.validateLength(o.Length, o.Length)
+	if err != nil {
+		return err
+	}
+	err = o.Policy.validateCharacters(AlphabetConsonant, AlphabetVowel)
+	if err != nil {
+		return err
+	}
+	return o.Policy.validateRequirements(o.Length, AlphabetConsonant, AlphabetVowel)
+}
+
+// Resolve returns a copy of the options with the length set to the shortest which meets the
+// entropy target. Options without an entropy target are returned unchanged.
+func (o PronounceableOptions) Resolve() (PronounceableOptions, error) {
+	if !(o.MinEntropy > 0) {
+		return o, nil
+	}
+
+	for o.Length = PasswordLengthMin; o.Length <= PasswordLengthMax; o.Length++ {
+		if o.entropy() >= o.MinEntropy {
+			return o, nil
+		}
+	}
+
+	return o, ErrEntropyUnreachable
+}
+
+// Entropy returns the number of bits of entropy in each password generated with the options. Each
+// consonant and vowel is chosen independently, so the entropy is the sum of the entropy of every
+// character position. Passwords discarded by the policy are not accounted for.
+func (o PronounceableOptions) Entropy() (float64, error) {
+	err := o.Validate()
+	if err != nil {
+		return 0, err
+	}
+
+	o, _ = o.Resolve()
+	return o.entropy(), nil
+}
+
+// entropy calculates the entropy of passwords of the configured length.
+func (o PronounceableOptions) entropy() float64 {
+	consonants := (o.Length + 1) / 2
+	vowels := o.Length / 2
+
+	return float64(consonants)*math.Log2(float64(len(AlphabetConsonant))) +
+		float64(vowels)*math.Log2(float64(len(AlphabetVowel)))
+}
+
+// GeneratePronounceable generates random pronounceable passwords based on the provided options,
+// using the default Generator backed by crypto/rand.
+func GeneratePronounceable(opts PronounceableOptions) ([]string, error) {
+	return defaultGenerator.Pronounceable(opts)
+}
+
+// Pronounceable generates random pronounceable passwords based on the provided options.
+//
+// Passwords which do not comply with the policy are discarded and generated anew, so the output is
+// uniformly distributed over every compliant password.
+func (g *Generator) Pronounceable(opts PronounceableOptions) (passwords []string, err error) {
+	// Validate the options.
+	err = opts.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	// Choose the password length if an entropy target was provided.
+	opts, err = opts.Resolve()
+	if err != nil {
+		return nil, err
+	}
+
+	// Determine how many bytes are needed to represent a password of the specified length.
+	// Rejected character indices are replaced by reading further random data.
+	bitsPerPassword := ((opts.Length+1)/2)*uint(bits.Len(uint(len(AlphabetConsonant)-1))) +
+		(opts.Length/2)*uint(bits.Len(uint(len(AlphabetVowel)-1)))
+	bytesPerPassword := bitsPerPassword / 8
+	if bitsPerPassword%8 > 0 {
+		bytesPerPassword++
+	}
+
+	var (
+		i        uint                                       // Password counter.
+		attempts uint                                       // Generation attempts for the current password.
+		charIdx  uint                                       // Character index within the current alphabet.
+		password = make([]byte, opts.Length)                // Buffer for constructing passwords.
+		source   = newBitReader(g.source, bytesPerPassword) // Bit reader for random data used as password source.
+	)
+
+	for i = 0; i < opts.Count; i++ {
+		for attempts = 0; ; attempts++ {
+			// Give up on a policy which is too unlikely to be met.
+			if attempts == GenerationAttemptsMax {
+				return nil, ErrRequirementsUnsatisfied
+			}
+
+			var j uint // Password character counter.
+
+			for j = 0; j < opts.Length; j++ {
+				// Alternate between consonants and vowels, beginning with a consonant.
+				alphabet := AlphabetConsonant
+				if j%2 == 1 {
+					alphabet = AlphabetVowel
+				}
+
+				// Select a uniformly distributed character index within the bounds of the alphabet.
+				charIdx, err = source.readIndex(uint(len(alphabet)))
+				if err != nil {
+					return nil, err
+				}
+
+				// Retrieve the character from the alphabet and write it to the password.
+				password[j] = alphabet[charIdx]
+			}
+
+			// Discard the password if it does not comply with the policy.
+			if opts.Policy.complies(string(password)) {
+				break
+			}
+		}
+
+		// Append the password to the return list.
+		passwords = append(passwords, string(password))
+	}
+
+	return
+}
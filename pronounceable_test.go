@@ -0,0 +1,173 @@
+package passgen
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeneratePronounceable(t *testing.T) {
+	type testReqs func(t *testing.T, passwords []string, err error)
+
+	type testDef struct {
+		name    string
+		options PronounceableOptions
+
+		requirements testReqs
+	}
+
+	var tests = []testDef{
+		{
+			"rational defaults",
+			DefaultPronounceableOptions(),
+
+			func(t *testing.T, passwords []string, err error) {
+				require.NoError(t, err)
+				require.Len(t, passwords, PasswordCountDefault)
+				for _, password := range passwords {
+					require.Len(t, password, PasswordLengthDefault)
+					for i, char := range password {
+						if i%2 == 0 {
+							require.Contains(t, AlphabetConsonant, string(char))
+						} else {
+							require.Contains(t, AlphabetVowel, string(char))
+						}
+					}
+				}
+			},
+		},
+		{
+			"odd length",
+			PronounceableOptions{Count: PasswordCountMax, Length: PasswordLengthMin},
+
+			func(t *testing.T, passwords []string, err error) {
+				require.NoError(t, err)
+				require.Len(t, passwords, PasswordCountMax)
+				for _, password := range passwords {
+					require.Len(t, password, PasswordLengthMin)
+					require.Contains(t, AlphabetConsonant, password[PasswordLengthMin-1:])
+				}
+			},
+		},
+		{
+			"entropy target",
+			PronounceableOptions{Count: PasswordCountDefault, MinEntropy: 64},
+
+			func(t *testing.T, passwords []string, err error) {
+				require.NoError(t, err)
+				require.Len(t, passwords, PasswordCountDefault)
+				require.Len(t, passwords[0], 21)
+			},
+		},
+		{
+			"count too small",
+			PronounceableOptions{Count: PasswordCountMin - 1, Length: PasswordLengthDefault},
+
+			func(t *testing.T, passwords []string, err error) {
+				require.Empty(t, passwords)
+				var rangeErr *RangeError
+				require.True(t, errors.As(err, &rangeErr))
+			},
+		},
+		{
+			"length too large",
+			PronounceableOptions{Count: PasswordCountDefault, Length: PasswordLengthMax + 1},
+
+			func(t *testing.T, passwords []string, err error) {
+				require.Empty(t, passwords)
+				var rangeErr *RangeError
+				require.True(t, errors.As(err, &rangeErr))
+			},
+		},
+		{
+			"entropy target beyond the length limit",
+			PronounceableOptions{Count: PasswordCountDefault, MinEntropy: 10000},
+
+			func(t *testing.T, passwords []string, err error) {
+				require.Empty(t, passwords)
+				require.True(t, errors.Is(err, ErrEntropyUnreachable))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name,
+			func(t *testing.T) {
+				passwords, err := GeneratePronounceable(test.options)
+				test.requirements(t, passwords, err)
+			},
+		)
+	}
+
+	t.Run(
+		"random source EOF",
+		func(t *testing.T) {
+			passwords, err := NewGenerator(new(bytes.Reader)).Pronounceable(DefaultPronounceableOptions())
+			require.Empty(t, passwords)
+			require.Error(t, err)
+		},
+	)
+}
+
+func TestPronounceableOptionsEntropy(t *testing.T) {
+	// Sixteen characters consist of eight consonants and eight vowels.
+	entropy, err := DefaultPronounceableOptions().Entropy()
+	require.NoError(t, err)
+	require.InDelta(t, 8*math.Log2(15)+8*math.Log2(5), entropy, 1e-9)
+
+	// The extra character of an odd length is a consonant.
+	entropy, err = PronounceableOptions{Count: PasswordCountDefault, Length: PasswordLengthMin}.Entropy()
+	require.NoError(t, err)
+	require.InDelta(t, 3*math.Log2(15)+2*math.Log2(5), entropy, 1e-9)
+
+	// Entropy targets are met by the shortest sufficient length.
+	opts, err := PronounceableOptions{Count: PasswordCountDefault, MinEntropy: 64}.Resolve()
+	require.NoError(t, err)
+	opts.MinEntropy = 0
+	entropy, err = opts.Entropy()
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, entropy, 64.0)
+	opts.Length--
+	entropy, err = opts.Entropy()
+	require.NoError(t, err)
+	require.Less(t, entropy, 64.0)
+
+	_, err = PronounceableOptions{}.Entropy()
+	require.Error(t, err)
+}
+
+func TestGeneratePronounceableDistribution(t *testing.T) {
+	// Use a seeded pseudorandom source so the test is reproducible.
+	generator := NewGenerator(rand.New(rand.NewSource(1)))
+
+	passwords, err := generator.Pronounceable(PronounceableOptions{
+		Count:  PasswordCountMax,
+		Length: PasswordLengthMax,
+	})
+	require.NoError(t, err)
+
+	// Tally how often each consonant and vowel was selected.
+	consonants := map[string]int{}
+	vowels := map[string]int{}
+	for _, password := range passwords {
+		for i, char := range password {
+			if strings.ContainsRune(AlphabetConsonant, char) {
+				require.Zero(t, i%2)
+				consonants[string(char)]++
+			} else {
+				require.Equal(t, 1, i%2)
+				vowels[string(char)]++
+			}
+		}
+	}
+
+	// Characters must be selected uniformly from their alphabets.
+	require.Less(t, chiSquared(consonants, len(AlphabetConsonant)), chiSquaredCritical(len(AlphabetConsonant)-1))
+	require.Less(t, chiSquared(vowels, len(AlphabetVowel)), chiSquaredCritical(len(AlphabetVowel)-1))
+}
@@ -0,0 +1,75 @@
+package passgen
+
+import (
+	"io"
+	"math/bits"
+)
+
+// bitReader reads individual bits from a random source, buffering the source data as needed.
+type bitReader struct {
+	source io.Reader // Random source to read data from.
+	buffer []byte    // Buffer of random data read from the source.
+	bitIdx uint      // Index of the next unread bit within the buffer.
+}
+
+// newBitReader constructs a bitReader which refills its buffer from the provided source in chunks
+// of the given size.
+func newBitReader(source io.Reader, size uint) *bitReader {
+	if size == 0 {
+		size = 1
+	}
+
+	buffer := make([]byte, size)
+
+	return &bitReader{
+		source: source,
+		buffer: buffer,
+		bitIdx: uint(len(buffer)) * 8,
+	}
+}
+
+// readBits reads the requested number of bits from the random source and returns them as an
+// unsigned integer.
+func (r *bitReader) readBits(n uint) (value uint, err error) {
+	var i uint
+	for i = 0; i < n; i++ {
+		// Refill the buffer once every bit within it has been consumed.
+		if r.bitIdx == uint(len(r.buffer))*8 {
+			_, err = io.ReadFull(r.source, r.buffer)
+			if err != nil {
+				return 0, err
+			}
+			r.bitIdx = 0
+		}
+
+		// Left shift the value to make room for the next bit.
+		value <<= 1
+
+		// Set the bit from the buffer in the value.
+		value |= uint(r.buffer[r.bitIdx/8]>>(7-r.bitIdx%8)) & 1
+
+		r.bitIdx++
+	}
+
+	return
+}
+
+// readIndex returns a uniformly distributed random index in the range [0, n).
+//
+// Indices are drawn using rejection sampling: the smallest number of bits capable of representing
+// n-1 is read and the result is discarded if it falls outside of the range. Reducing the value
+// modulo n instead would bias the output towards lower indices whenever n is not a power of two.
+func (r *bitReader) readIndex(n uint) (uint, error) {
+	bitsPerIdx := uint(bits.Len(n - 1))
+
+	for {
+		idx, err := r.readBits(bitsPerIdx)
+		if err != nil {
+			return 0, err
+		}
+
+		if idx < n {
+			return idx, nil
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package passgen
+
+import (
+	"bytes"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// chiSquared calculates Pearson's chi-squared statistic for the observed counts against a uniform
+// distribution over the provided number of categories.
+func chiSquared(counts map[string]int, categories int) float64 {
+	var total int
+	for _, count := range counts {
+		total += count
+	}
+	expected := float64(total) / float64(categories)
+
+	var statistic float64
+	for _, count := range counts {
+		statistic += math.Pow(float64(count)-expected, 2) / expected
+	}
+
+	// Categories which were never observed still contribute to the statistic.
+	statistic += float64(categories-len(counts)) * expected
+
+	return statistic
+}
+
+// chiSquaredCritical approximates the critical value of the chi-squared distribution with the
+// provided degrees of freedom at a significance level of 0.001, using the Wilson-Hilferty
+// transformation.
+func chiSquaredCritical(degreesOfFreedom int) float64 {
+	const z = 3.090232 // Standard normal quantile for 0.999.
+	k := float64(degreesOfFreedom)
+	return k * math.Pow(1-2/(9*k)+z*math.Sqrt(2/(9*k)), 3)
+}
+
+func TestBitReaderReadIndex(t *testing.T) {
+	type testDef struct {
+		name string
+		n    uint
+	}
+
+	var tests = []testDef{
+		{"power of two", 8},
+		{"one more than a power of two", 9},
+		{"one less than a power of two", 7},
+		{"default alphabet size", uint(len(AlphabetDefault))},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name,
+			func(t *testing.T) {
+				source := newBitReader(rand.New(rand.NewSource(1)), 64)
+
+				counts := map[string]int{}
+				for i := 0; i < 100000; i++ {
+					idx, err := source.readIndex(test.n)
+					require.NoError(t, err)
+					require.Less(t, idx, test.n)
+					counts[string(rune(idx))]++
+				}
+
+				require.Less(t, chiSquared(counts, int(test.n)), chiSquaredCritical(int(test.n)-1))
+			},
+		)
+	}
+
+	t.Run(
+		"random source EOF",
+		func(t *testing.T) {
+			source := newBitReader(new(bytes.Reader), 64)
+			_, err := source.readIndex(2)
+			require.Error(t, err)
+		},
+	)
+}
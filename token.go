@@ -0,0 +1,183 @@
+package passgen
+
+import (
+	"hash/crc32"
+	"io"
+	"math"
+	"math/big"
+	"strings"
+)
+
+// TokenOptions configures the generation of API tokens. Tokens take the form
+// <prefix>_<body><checksum>, where the body is random data encoded in base62 and the checksum is a
+// base62-encoded CRC32 of everything preceding it. The prefix identifies the kind of token, which
+// lets secret scanners recognize leaked tokens, and the checksum lets them discard false positives
+// without contacting the issuer.
+type TokenOptions struct {
+	Count  uint   // Number of tokens to generate.
+	Prefix string // Prefix identifying the kind of token.
+	Bytes  uint   // Number of random bytes encoded in each token body.
+}
+
+// DefaultTokenOptions returns token options populated with the package defaults.
+func DefaultTokenOptions() TokenOptions {
+	return TokenOptions{
+		Count:  TokenCountDefault,
+		Prefix: TokenPrefixDefault,
+		Bytes:  TokenBytesDefault,
+	}
+}
+
+// Validate checks the options against the package limits. A *RangeError is returned for an out of
+// bounds count or byte count, and ErrInvalidTokenPrefix for an invalid prefix.
+func (o TokenOptions) Validate() error {
+	// Validate the supplied count parameter.
+	if o.Count < TokenCountMin || o.Count > TokenCountMax {
+		return &RangeError{"count", TokenCountMin, TokenCountMax}
+	}
+
+	// Validate the supplied byte count parameter.
+	if o.Bytes < TokenBytesMin || o.Bytes > TokenBytesMax {
+		return &RangeError{"bytes", TokenBytesMin, TokenBytesMax}
+	}
+
+	// Validate the supplied prefix parameter.
+	if !isTokenPrefix(o.Prefix) {
+		return ErrInvalidTokenPrefix
+	}
+
+	return nil
+}
+
+// Entropy returns the number of bits of entropy in each token generated with the options.
+func (o TokenOptions) Entropy() (float64, error) {
+	err := o.Validate()
+	if err != nil {
+		return 0, err
+	}
+
+	return float64(o.Bytes) * 8, nil
+}
+
+// Length returns the length, in characters, of each token generated with the options.
+func (o TokenOptions) Length() uint {
+	return uint(len(o.Prefix)) + 1 + base62Length(o.Bytes) + TokenChecksumLength
+}
+
+// isTokenPrefix reports whether the prefix is a valid token prefix.
+func isTokenPrefix(prefix string) bool {
+	if len(prefix) == 0 || len(prefix) > TokenPrefixLengthMax {
+		return false
+	}
+
+	return isBase62(prefix)
+}
+
+// isBase62 reports whether the string consists solely of base62 characters.
+func isBase62(s string) bool {
+	for _, char := range s {
+		if !(char >= '0' && char <= '9') && !(char >= 'a' && char <= 'z') && !(char >= 'A' && char <= 'Z') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// base62Length returns the number of base62 characters needed to encode the given number of
+// bytes.
+func base62Length(bytes uint) uint {
+	return uint(math.Ceil(float64(bytes) * 8 / math.Log2(62)))
+}
+
+// encodeBase62 encodes the data as a base62 number, left-padded with zeros to the given width.
+func encodeBase62(data []byte, width uint) string {
+	encoded := new(big.Int).SetBytes(data).Text(62)
+	if uint(len(encoded)) < width {
+		encoded = strings.Repeat("0", int(width)-len(encoded)) + encoded
+	}
+
+	return encoded
+}
+
+// tokenChecksum returns the base62-encoded CRC32 checksum of the token prefix and body.
+func tokenChecksum(prefixedBody string) string {
+	checksum := crc32.ChecksumIEEE([]byte(prefixedBody))
+	return encodeBase62([]byte{
+		byte(checksum >> 24),
+		byte(checksum >> 16),
+		byte(checksum >> 8),
+		byte(checksum),
+	}, TokenChecksumLength)
+}
+
+// ValidateToken checks that the token carries the expected prefix and a valid checksum, without
+// contacting the token issuer. ErrTokenPrefix is returned for a token with a different prefix,
+// ErrTokenFormat for a token which is not correctly structured, and ErrTokenChecksum for a token
+// whose checksum does not match, such as one containing a typo.
+func ValidateToken(token string, prefix string) error {
+	// Ensure the token begins with the prefix and its separator.
+	if !strings.HasPrefix(token, prefix+"_") {
+		return ErrTokenPrefix
+	}
+
+	// Ensure the remainder of the token consists of a body followed by a checksum.
+	rest := token[len(prefix)+1:]
+	if len(rest) <= TokenChecksumLength || !isBase62(rest) {
+		return ErrTokenFormat
+	}
+
+	// Ensure the checksum matches the prefix and body.
+	checksumIdx := len(token) - TokenChecksumLength
+	if tokenChecksum(token[:checksumIdx]) != token[checksumIdx:] {
+		return ErrTokenChecksum
+	}
+
+	return nil
+}
+
+// GenerateTokens generates random API tokens based on the provided options, using the default
+// Generator backed by crypto/rand.
+func GenerateTokens(opts TokenOptions) ([]string, error) {
+	return defaultGenerator.Tokens(opts)
+}
+
+// Tokens generates random API tokens based on the provided options.
+func (g *Generator) Tokens(opts TokenOptions) (tokens []string, err error) {
+	// Validate the options.
+	err = opts.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		i          uint                       // Token counter.
+		b          strings.Builder            // String builder for efficiently constructing tokens.
+		bodyBuffer = make([]byte, opts.Bytes) // Byte buffer for random data used as token body.
+		bodyLength = base62Length(opts.Bytes) // Length of the encoded token body.
+	)
+
+	for i = 0; i < opts.Count; i++ {
+		// Read the random data making up the token body.
+		_, err = io.ReadFull(g.source, bodyBuffer)
+		if err != nil {
+			return nil, err
+		}
+
+		// Write the prefix and encoded body to the token.
+		b.WriteString(opts.Prefix)
+		b.WriteByte('_')
+		b.WriteString(encodeBase62(bodyBuffer, bodyLength))
+
+		// Write the checksum of everything preceding it to the token.
+		b.WriteString(tokenChecksum(b.String()))
+
+		// Append the token to the return list.
+		tokens = append(tokens, b.String())
+
+		// Reset the string builder for the next token.
+		b.Reset()
+	}
+
+	return
+}
@@ -0,0 +1,188 @@
+package passgen
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateTokens(t *testing.T) {
+	type testReqs func(t *testing.T, tokens []string, err error)
+
+	type testDef struct {
+		name    string
+		options TokenOptions
+
+		requirements testReqs
+	}
+
+	var tests = []testDef{
+		{
+			"rational defaults",
+			DefaultTokenOptions(),
+
+			func(t *testing.T, tokens []string, err error) {
+				require.NoError(t, err)
+				require.Len(t, tokens, TokenCountDefault)
+				for _, token := range tokens {
+					require.True(t, strings.HasPrefix(token, TokenPrefixDefault+"_"))
+					require.EqualValues(t, DefaultTokenOptions().Length(), len(token))
+					require.NoError(t, ValidateToken(token, TokenPrefixDefault))
+				}
+			},
+		},
+		{
+			"non-default parameters",
+			TokenOptions{Count: TokenCountMax, Prefix: "svc2", Bytes: TokenBytesMin},
+
+			func(t *testing.T, tokens []string, err error) {
+				require.NoError(t, err)
+				require.Len(t, tokens, TokenCountMax)
+				for _, token := range tokens {
+					require.Len(t, token, 5+22+TokenChecksumLength)
+					require.NoError(t, ValidateToken(token, "svc2"))
+				}
+			},
+		},
+		{
+			"count too large",
+			TokenOptions{Count: TokenCountMax + 1, Prefix: TokenPrefixDefault, Bytes: TokenBytesDefault},
+
+			func(t *testing.T, tokens []string, err error) {
+				require.Empty(t, tokens)
+				var rangeErr *RangeError
+				require.True(t, errors.As(err, &rangeErr))
+			},
+		},
+		{
+			"bytes too small",
+			TokenOptions{Count: TokenCountDefault, Prefix: TokenPrefixDefault, Bytes: TokenBytesMin - 1},
+
+			func(t *testing.T, tokens []string, err error) {
+				require.Empty(t, tokens)
+				var rangeErr *RangeError
+				require.True(t, errors.As(err, &rangeErr))
+			},
+		},
+		{
+			"bytes too large",
+			TokenOptions{Count: TokenCountDefault, Prefix: TokenPrefixDefault, Bytes: TokenBytesMax + 1},
+
+			func(t *testing.T, tokens []string, err error) {
+				require.Empty(t, tokens)
+				var rangeErr *RangeError
+				require.True(t, errors.As(err, &rangeErr))
+			},
+		},
+		{
+			"empty prefix",
+			TokenOptions{Count: TokenCountDefault, Prefix: "", Bytes: TokenBytesDefault},
+
+			func(t *testing.T, tokens []string, err error) {
+				require.Empty(t, tokens)
+				require.True(t, errors.Is(err, ErrInvalidTokenPrefix))
+			},
+		},
+		{
+			"prefix containing separator",
+			TokenOptions{Count: TokenCountDefault, Prefix: "my_svc", Bytes: TokenBytesDefault},
+
+			func(t *testing.T, tokens []string, err error) {
+				require.Empty(t, tokens)
+				require.True(t, errors.Is(err, ErrInvalidTokenPrefix))
+			},
+		},
+		{
+			"prefix too long",
+			TokenOptions{
+				Count:  TokenCountDefault,
+				Prefix: strings.Repeat("x", TokenPrefixLengthMax+1),
+				Bytes:  TokenBytesDefault,
+			},
+
+			func(t *testing.T, tokens []string, err error) {
+				require.Empty(t, tokens)
+				require.True(t, errors.Is(err, ErrInvalidTokenPrefix))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name,
+			func(t *testing.T) {
+				tokens, err := GenerateTokens(test.options)
+				test.requirements(t, tokens, err)
+			},
+		)
+	}
+
+	t.Run(
+		"deterministic body encoding",
+		func(t *testing.T) {
+			opts := DefaultTokenOptions()
+			tokens, err := NewGenerator(bytes.NewReader(make([]byte, opts.Bytes))).Tokens(opts)
+			require.NoError(t, err)
+			prefixedBody := TokenPrefixDefault + "_" + strings.Repeat("0", 43)
+			require.Equal(t, []string{prefixedBody + tokenChecksum(prefixedBody)}, tokens)
+		},
+	)
+
+	t.Run(
+		"random source EOF",
+		func(t *testing.T) {
+			tokens, err := NewGenerator(new(bytes.Reader)).Tokens(DefaultTokenOptions())
+			require.Empty(t, tokens)
+			require.Error(t, err)
+		},
+	)
+}
+
+func TestValidateToken(t *testing.T) {
+	tokens, err := GenerateTokens(DefaultTokenOptions())
+	require.NoError(t, err)
+	token := tokens[0]
+
+	// Swap a single body character to simulate a typo.
+	typo := []byte(token)
+	bodyIdx := len(TokenPrefixDefault) + 1
+	if typo[bodyIdx] == 'a' {
+		typo[bodyIdx] = 'b'
+	} else {
+		typo[bodyIdx] = 'a'
+	}
+
+	type testDef struct {
+		name   string
+		token  string
+		prefix string
+		err    error
+	}
+
+	var tests = []testDef{
+		{"valid token", token, TokenPrefixDefault, nil},
+		{"different prefix", token, "other", ErrTokenPrefix},
+		{"missing separator", strings.Replace(token, "_", "", 1), TokenPrefixDefault, ErrTokenPrefix},
+		{"truncated token", token[:bodyIdx+TokenChecksumLength], TokenPrefixDefault, ErrTokenFormat},
+		{"non-base62 characters", token[:len(token)-1] + "!", TokenPrefixDefault, ErrTokenFormat},
+		{"typo in body", string(typo), TokenPrefixDefault, ErrTokenChecksum},
+		{"altered checksum", token[:len(token)-TokenChecksumLength] + "000000", TokenPrefixDefault, ErrTokenChecksum},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name,
+			func(t *testing.T) {
+				err := ValidateToken(test.token, test.prefix)
+				if test.err == nil {
+					require.NoError(t, err)
+				} else {
+					require.True(t, errors.Is(err, test.err))
+				}
+			},
+		)
+	}
+}
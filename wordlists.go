@@ -0,0 +1,263 @@
+package passgen
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Text of each built-in word list, with one word per line.
+var (
+	//go:embed wordlists/eff-large.txt
+	wordListEFFLarge string
+
+	//go:embed wordlists/eff-short-1.txt
+	wordListEFFShort1 string
+
+	//go:embed wordlists/eff-short-2.txt
+	wordListEFFShort2 string
+
+	//go:embed wordlists/reinhold.txt
+	wordListReinhold string
+
+	//go:embed wordlists/bip39-english.txt
+	wordListBIP39English string
+)
+
+var (
+	// WordListEFFLarge is the EFF large word list, designed for choosing each word with five dice.
+	// See: https://www.eff.org/dice
+	WordListEFFLarge = strings.Fields(wordListEFFLarge)
+
+	// WordListEFFShort1 is the first EFF short word list, designed for choosing each word with four
+	// dice. Its words are short and easy to type.
+	// See: https://www.eff.org/dice
+	WordListEFFShort1 = strings.Fields(wordListEFFShort1)
+
+	// WordListEFFShort2 is the second EFF short word list, designed for choosing each word with four
+	// dice. Each of its words is uniquely identified by its first three letters.
+	// See: https://www.eff.org/dice
+	WordListEFFShort2 = strings.Fields(wordListEFFShort2)
+
+	// WordListReinhold is Arnold Reinhold's original diceware word list, designed for choosing each
+	// word with five dice.
+	// See: https://theworld.com/~reinhold/diceware.html
+	WordListReinhold = strings.Fields(wordListReinhold)
+
+	// WordListBIP39English is the English word list defined by BIP39 for encoding mnemonics. Each of
+	// its 2048 words is uniquely identified by its first four letters.
+	// See: https://github.com/bitcoin/bips/blob/master/bip-0039/english.txt
+	WordListBIP39English = strings.Fields(wordListBIP39English)
+
+	// WordListDefault is a set of words from EFF for use with their passphrase dice method of
+	// deriving passphrases.
+	// See: https://www.eff.org/dice
+	WordListDefault = WordListEFFLarge
+)
+
+// NamedWordList is a built-in word list selectable by name.
+type NamedWordList struct {
+	Name        string   // Name used to select the word list.
+	Description string   // Short description of the word list.
+	Words       []string // Words making up the word list.
+}
+
+// Size returns the number of unique words in the word list.
+func (l NamedWordList) Size() uint {
+	return uint(len(PassphraseOptions{Casing: PassphraseCasingNone, WordList: l.Words}.uniqueWords()))
+}
+
+// BitsPerWord returns the number of bits of entropy in each word chosen from the word list.
+func (l NamedWordList) BitsPerWord() float64 {
+	return math.Log2(float64(l.Size()))
+}
+
+// WordLists are the built-in word lists, beginning with the default.
+var WordLists = []NamedWordList{
+	{"eff-large", "EFF large list for five dice (default)", WordListEFFLarge},
+	{"eff-short-1", "EFF short list for four dice, with short words", WordListEFFShort1},
+	{"eff-short-2", "EFF short list for four dice, with unique three-letter prefixes", WordListEFFShort2},
+	{"reinhold", "Arnold Reinhold's original diceware list for five dice", WordListReinhold},
+	{"bip39-english", "BIP39 English list, with unique four-letter prefixes", WordListBIP39English},
+}
+
+// LookupWordList returns the words of the built-in word list with the given name.
+// ErrUnknownWordList is returned if there is no such list.
+func LookupWordList(name string) ([]string, error) {
+	for _, list := range WordLists {
+		if list.Name == name {
+			return list.Words, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %q", ErrUnknownWordList, name)
+}
+
+// wordListFormat is the layout of the lines within a word list file.
+type wordListFormat uint8
+
+// Word list formats recognized by LoadWordList.
+const (
+	wordListPlain        wordListFormat = iota // One word per line.
+	wordListDiceNumbered                       // A dice roll and a word per line, e.g. "11111 abacus".
+	wordListTabSeparated                       // A key, a tab, and a word per line.
+)
+
+// Longest dice roll accepted in a dice-numbered word list, bounding the list to 6^8 words.
+const wordListRollLengthMax = 8
+
+// LoadWordList reads a word list, detecting its format from the first word line.
+//
+// Plain lists have one word per line. Dice-numbered lists, such as those published by EFF, begin
+// each line with a dice roll followed by whitespace and a word; their words are returned in roll
+// order, and every roll must appear exactly once. Tab-separated lists have a key, a tab, and a word
+// on each line. Blank lines and lines beginning with '#' are skipped, and words are normalized to
+// Unicode NFC so that visually identical words compare equal.
+//
+// A *WordListError identifying the line is returned for malformed input, and ErrWordListMissing for
+// a dice-numbered list which does not cover every roll.
+func LoadWordList(r io.Reader) ([]string, error) {
+	var (
+		lineNum    uint           // Number of the current line.
+		format     wordListFormat // Format detected from the first word line.
+		detected   bool           // Whether the format has been detected.
+		rollLength int            // Length of each roll in a dice-numbered list.
+		words      []string       // Words read from the list, in file order.
+		rolls      = map[uint]string{}
+		rollLines  = map[uint]uint{} // Line on which each roll was read, for reporting duplicates.
+	)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Text()
+
+		// Ensure the line is valid UTF-8 before normalizing it, ignoring any byte order mark.
+		if lineNum == 1 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+		if !utf8.ValidString(line) {
+			return nil, &WordListError{lineNum, "invalid UTF-8"}
+		}
+		line = norm.NFC.String(strings.TrimSpace(line))
+
+		// Skip blank lines and comments.
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// Detect the format from the first word line.
+		fields := strings.Fields(line)
+		if !detected {
+			switch {
+			case len(fields) == 2 && isDiceRoll(fields[0]) && len(fields[0]) <= wordListRollLengthMax:
+				format = wordListDiceNumbered
+				rollLength = len(fields[0])
+			case strings.Contains(line, "\t"):
+				format = wordListTabSeparated
+			default:
+				format = wordListPlain
+			}
+			detected = true
+		}
+
+		switch format {
+		case wordListPlain:
+			if len(fields) != 1 {
+				return nil, &WordListError{lineNum, "expected a single word"}
+			}
+			words = append(words, line)
+
+		case wordListDiceNumbered:
+			if len(fields) != 2 {
+				return nil, &WordListError{lineNum, "expected a dice roll followed by a word"}
+			}
+			if len(fields[0]) != rollLength || !isDiceRoll(fields[0]) {
+				return nil, &WordListError{
+					lineNum,
+					fmt.Sprintf("expected a dice roll of %d digits from 1 to %d, got %q", rollLength, DiceSides, fields[0]),
+				}
+			}
+
+			// Index the word by its roll, rejecting rolls which were already read.
+			idx := diceRollIndex(fields[0])
+			if prevLine, ok := rollLines[idx]; ok {
+				return nil, &WordListError{lineNum, fmt.Sprintf("dice roll %s already used on line %d", fields[0], prevLine)}
+			}
+			rolls[idx] = fields[1]
+			rollLines[idx] = lineNum
+
+		case wordListTabSeparated:
+			columns := strings.Split(line, "\t")
+			if len(columns) != 2 {
+				return nil, &WordListError{lineNum, "expected a key and a word separated by a tab"}
+			}
+			word := strings.TrimSpace(columns[1])
+			if word == "" || len(strings.Fields(word)) != 1 {
+				return nil, &WordListError{lineNum, "expected a single word after the tab"}
+			}
+			words = append(words, word)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// Order the words of a dice-numbered list by roll, ensuring every roll is present.
+	if format == wordListDiceNumbered {
+		rollCount := uint(math.Pow(DiceSides, float64(rollLength)))
+		words = make([]string, rollCount)
+		for idx := uint(0); idx < rollCount; idx++ {
+			word, ok := rolls[idx]
+			if !ok {
+				return nil, fmt.Errorf("%w: %s", ErrWordListMissing, diceRoll(idx, rollLength))
+			}
+			words[idx] = word
+		}
+	}
+
+	return words, nil
+}
+
+// isDiceRoll reports whether the string is a non-empty sequence of digits from 1 to 6.
+func isDiceRoll(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for i := 0; i < len(s); i++ {
+		if s[i] < '1' || s[i] > '0'+DiceSides {
+			return false
+		}
+	}
+
+	return true
+}
+
+// diceRollIndex returns the index chosen by the dice roll, which must be valid, treating each die
+// as a digit in base six.
+func diceRollIndex(roll string) uint {
+	var idx uint
+	for i := 0; i < len(roll); i++ {
+		idx = idx*DiceSides + uint(roll[i]-'1')
+	}
+
+	return idx
+}
+
+// diceRoll returns the dice roll of the given length which chooses the index.
+func diceRoll(idx uint, length int) string {
+	roll := make([]byte, length)
+	for i := length - 1; i >= 0; i-- {
+		roll[i] = '1' + byte(idx%DiceSides)
+		idx /= DiceSides
+	}
+
+	return string(roll)
+}
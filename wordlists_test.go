@@ -0,0 +1,269 @@
+package passgen
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWordLists(t *testing.T) {
+	type testDef struct {
+		name         string
+		size         uint
+		prefixLength int // Length of the prefix uniquely identifying each word, if any.
+	}
+
+	var tests = []testDef{
+		{"eff-large", 7776, 0},
+		{"eff-short-1", 1296, 0},
+		{"eff-short-2", 1296, 3},
+		{"reinhold", 7776, 0},
+		{"bip39-english", 2048, 4},
+	}
+
+	require.Len(t, WordLists, len(tests))
+
+	for i, test := range tests {
+		t.Run(
+			test.name,
+			func(t *testing.T) {
+				require.Equal(t, test.name, WordLists[i].Name)
+
+				words, err := LookupWordList(test.name)
+				require.NoError(t, err)
+				require.EqualValues(t, test.size, len(words))
+				require.Equal(t, test.size, WordLists[i].Size())
+				require.InDelta(t, math.Log2(float64(test.size)), WordLists[i].BitsPerWord(), 1e-9)
+
+				// Ensure every word is non-empty and, where promised, uniquely identified by its prefix.
+				prefixes := map[string]struct{}{}
+				for _, word := range words {
+					require.NotEmpty(t, word)
+					if test.prefixLength == 0 || len(word) < test.prefixLength {
+						continue
+					}
+					prefix := word[:test.prefixLength]
+					require.NotContains(t, prefixes, prefix)
+					prefixes[prefix] = struct{}{}
+				}
+			},
+		)
+	}
+
+	t.Run(
+		"default is EFF large list",
+		func(t *testing.T) {
+			require.Equal(t, WordListEFFLarge, WordListDefault)
+			require.Equal(t, "abacus", WordListDefault[0])
+			require.Equal(t, "zoom", WordListDefault[len(WordListDefault)-1])
+		},
+	)
+
+	t.Run(
+		"unknown list",
+		func(t *testing.T) {
+			words, err := LookupWordList("eff-tiny")
+			require.Empty(t, words)
+			require.True(t, errors.Is(err, ErrUnknownWordList))
+		},
+	)
+}
+
+func TestLoadWordList(t *testing.T) {
+	type testDef struct {
+		name  string
+		input string
+
+		words   []string // Expected words, if the list is valid.
+		errLine uint     // Line of the expected *WordListError, if any.
+		err     error    // Expected error, if any other.
+	}
+
+	// Build a complete dice-numbered list with single-die rolls, listed out of order.
+	diceList := "# Example list\n\n3\tcharlie\n1\talfa\n2\tbravo\n4 delta\n6 foxtrot\n5 echo\n"
+
+	// Build a complete dice-numbered list with rolls of two dice, listed in reverse.
+	var (
+		twoDiceList  strings.Builder
+		twoDiceWords = make([]string, 36)
+	)
+	for i := len(twoDiceWords) - 1; i >= 0; i-- {
+		twoDiceWords[i] = fmt.Sprintf("word%d", i)
+		fmt.Fprintf(&twoDiceList, "%s\t%s\n", diceRoll(uint(i), 2), twoDiceWords[i])
+	}
+
+	var tests = []testDef{
+		{
+			"plain",
+			"alfa\nbravo\ncharlie\n",
+			[]string{"alfa", "bravo", "charlie"},
+			0,
+			nil,
+		},
+		{
+			"plain without trailing newline",
+			"alfa\r\nbravo\r\ncharlie",
+			[]string{"alfa", "bravo", "charlie"},
+			0,
+			nil,
+		},
+		{
+			"comments, blank lines, and surrounding whitespace",
+			"\ufeff# Heading\n\n  alfa  \n# Comment\n\tbravo\n\n",
+			[]string{"alfa", "bravo"},
+			0,
+			nil,
+		},
+		{
+			"dice-numbered in roll order",
+			diceList,
+			[]string{"alfa", "bravo", "charlie", "delta", "echo", "foxtrot"},
+			0,
+			nil,
+		},
+		{
+			"dice-numbered with multiple dice",
+			twoDiceList.String(),
+			twoDiceWords,
+			0,
+			nil,
+		},
+		{
+			"tab-separated",
+			"a\talfa\nb\tbravo\nc\tcharlie\n",
+			[]string{"alfa", "bravo", "charlie"},
+			0,
+			nil,
+		},
+		{
+			"unicode normalization",
+			"cafe\u0301\nna\u0131ve\n",
+			[]string{"caf\u00e9", "na\u0131ve"},
+			0,
+			nil,
+		},
+		{
+			"plain line with multiple words",
+			"alfa\n# Comment\nbravo charlie\n",
+			nil,
+			3,
+			nil,
+		},
+		{
+			"tab-separated line without tab",
+			"a\talfa\nbravo\n",
+			nil,
+			2,
+			nil,
+		},
+		{
+			"tab-separated line with multiple words",
+			"a\talfa\nb\tbravo charlie\n",
+			nil,
+			2,
+			nil,
+		},
+		{
+			"dice-numbered line with invalid roll",
+			"1 alfa\n7 bravo\n",
+			nil,
+			2,
+			nil,
+		},
+		{
+			"dice-numbered line with inconsistent roll length",
+			"1 alfa\n22 bravo\n",
+			nil,
+			2,
+			nil,
+		},
+		{
+			"dice-numbered line without word",
+			"1 alfa\n2\n",
+			nil,
+			2,
+			nil,
+		},
+		{
+			"dice-numbered duplicate roll",
+			"1 alfa\n2 bravo\n1 charlie\n",
+			nil,
+			3,
+			nil,
+		},
+		{
+			"dice-numbered missing roll",
+			"1 alfa\n2 bravo\n3 charlie\n4 delta\n6 foxtrot\n",
+			nil,
+			0,
+			ErrWordListMissing,
+		},
+		{
+			"invalid UTF-8",
+			"alfa\nbr\xffvo\n",
+			nil,
+			2,
+			nil,
+		},
+		{
+			"empty",
+			"# Nothing here\n\n",
+			nil,
+			0,
+			nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name,
+			func(t *testing.T) {
+				words, err := LoadWordList(strings.NewReader(test.input))
+
+				switch {
+				case test.errLine > 0:
+					var wordListErr *WordListError
+					require.True(t, errors.As(err, &wordListErr), "unexpected error: %v", err)
+					require.Equal(t, test.errLine, wordListErr.Line)
+					require.Empty(t, words)
+				case test.err != nil:
+					require.True(t, errors.Is(err, test.err), "unexpected error: %v", err)
+					require.Empty(t, words)
+				default:
+					require.NoError(t, err)
+					if test.words != nil {
+						require.Equal(t, test.words, words)
+					}
+				}
+			},
+		)
+	}
+
+	t.Run(
+		"built-in lists round trip",
+		func(t *testing.T) {
+			for _, list := range WordLists {
+				// Diceware lists are written dice-numbered, as published, since words such as '#' in
+				// the Reinhold list would otherwise be read as comments.
+				var (
+					b            strings.Builder
+					diceNumbered = list.Size() == DiceWordListLength
+				)
+				for i, word := range list.Words {
+					if diceNumbered {
+						fmt.Fprintf(&b, "%s\t", diceRoll(uint(i), DiceRollsPerWord))
+					}
+					fmt.Fprintln(&b, word)
+				}
+
+				words, err := LoadWordList(strings.NewReader(b.String()))
+				require.NoError(t, err)
+				require.Equal(t, list.Words, words)
+			}
+		},
+	)
+}